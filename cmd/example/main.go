@@ -17,7 +17,7 @@ func main() {
 	fmt.Println("=== パトリシアトライの使用例 ===")
 
 	for _, key := range keys {
-		err := trie.Insert(key)
+		_, err := trie.Insert(key)
 		if err != nil {
 			log.Fatalf("キーの挿入に失敗: %v", err)
 		}
@@ -5,9 +5,11 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,10 +25,11 @@ const (
 	maxSuggestions  = 10
 	maxHistoryItems = 1000
 	dirPermission   = 0750
+	savedTrieExt    = ".ptrie"
 )
 
 var (
-	trie    *patriciatrie.Trie
+	trie    *patriciatrie.Trie[any]
 	verbose bool
 	history []string
 	green   = color.New(color.FgGreen).SprintFunc()
@@ -52,6 +55,7 @@ func main() {
 	// コマンドラインフラグの定義
 	verboseFlag := flag.Bool("verbose", false, "Verboseモードで開始")
 	verboseFlagShort := flag.Bool("v", false, "Verboseモードで開始（短縮形）")
+	saveFlag := flag.String("save", "", "構築したトライをバイナリ形式で保存するパス")
 	flag.Usage = showUsage
 	flag.Parse()
 
@@ -71,11 +75,24 @@ func main() {
 
 	var buildStats BuildStats
 
-	trie, buildStats, err = buildTrie(wordlistPath)
+	// 保存済みのトライはワードリストを解析せずにそのまま読み込む
+	if filepath.Ext(wordlistPath) == savedTrieExt {
+		trie, buildStats, err = loadTrie(wordlistPath)
+	} else {
+		trie, buildStats, err = buildTrie(wordlistPath)
+	}
+
 	if err != nil {
 		log.Fatalf("Failed to load wordlist: %v", err)
 	}
 
+	if *saveFlag != "" {
+		err = saveTrie(trie, *saveFlag)
+		if err != nil {
+			log.Fatalf("Failed to save trie: %v", err)
+		}
+	}
+
 	// Verboseモードでトライ構築統計を表示
 	if verbose {
 		memoryMB := float64(buildStats.MemoryUsed) / bytesPerMB
@@ -94,7 +111,7 @@ func main() {
 		verboseStatus = " (Verbose mode enabled)"
 	}
 
-	fmt.Printf("\n%s REPL started%s. Commands: /exit, /quit, /verbose, /help\n", cyan("Patricia Trie"), verboseStatus)
+	fmt.Printf("\n%s REPL started%s. Commands: /exit, /quit, /verbose, /stats, /help\n", cyan("Patricia Trie"), verboseStatus)
 	fmt.Printf("Use Tab for auto-completion. Emacs key bindings are enabled.\n\n")
 
 	// go-promptの起動
@@ -138,6 +155,8 @@ func executor(input string) {
 		}
 
 		fmt.Printf("%s Verbose mode %s\n", yellow("[info]"), status)
+	case "/stats":
+		showTrieStats()
 	case "/help":
 		showHelp()
 	default:
@@ -153,6 +172,7 @@ func completer(d prompt.Document) []prompt.Suggest {
 		return []prompt.Suggest{
 			{Text: "/help", Description: "Show help message"},
 			{Text: "/verbose", Description: "Toggle verbose mode"},
+			{Text: "/stats", Description: "Show trie structure statistics"},
 			{Text: "/exit", Description: "Exit the REPL"},
 		}
 	}
@@ -164,6 +184,7 @@ func completer(d prompt.Document) []prompt.Suggest {
 		commands := []prompt.Suggest{
 			{Text: "/help", Description: "Show help message"},
 			{Text: "/verbose", Description: "Toggle verbose mode"},
+			{Text: "/stats", Description: "Show trie structure statistics"},
 			{Text: "/exit", Description: "Exit the REPL"},
 			{Text: "/quit", Description: "Exit the REPL"},
 		}
@@ -214,14 +235,18 @@ func showUsage() {
 
 引数:
   wordlist    単語リストファイル（1行に1単語、UTF-8エンコーディング）
+              または -save で保存したトライ（拡張子 .ptrie）
 
 オプション:
   -v, --verbose    Verboseモードで開始（検索統計情報を表示）
+  -save <path>     構築したトライをバイナリ形式で保存（次回から <path> を直接指定できる）
 
 例:
   patricia-repl words.txt
   patricia-repl --verbose /path/to/dictionary.txt
   patricia-repl -v words.txt
+  patricia-repl -save words.ptrie words.txt
+  patricia-repl words.ptrie
 
 ワードリストファイルの形式:
   cat
@@ -233,6 +258,7 @@ func showUsage() {
 起動後のコマンド:
   /help     - ヘルプメッセージを表示
   /verbose  - Verboseモードの切り替え
+  /stats    - トライの構造統計を表示
   /exit     - 終了
   /quit     - 終了
 
@@ -253,6 +279,7 @@ func showHelp() {
 Commands:
   /help     - Show this help message
   /verbose  - Toggle verbose mode (currently: %s)
+  /stats    - Show trie structure statistics
   /exit     - Exit the REPL
   /quit     - Exit the REPL
 
@@ -283,7 +310,7 @@ Key bindings (Emacs mode):
 }
 
 // buildTrie はワードリストファイルからパトリシアトライを構築
-func buildTrie(path string) (*patriciatrie.Trie, BuildStats, error) {
+func buildTrie(path string) (*patriciatrie.Trie[any], BuildStats, error) {
 	// ガベージコレクションを実行してより正確なメモリ測定を行う
 	runtime.GC()
 	
@@ -301,9 +328,9 @@ func buildTrie(path string) (*patriciatrie.Trie, BuildStats, error) {
 		_ = file.Close()
 	}()
 
-	trie := patriciatrie.New()
 	scanner := bufio.NewScanner(file)
-	lineCount := 0
+
+	var words []string
 
 	for scanner.Scan() {
 		word := strings.TrimSpace(scanner.Text())
@@ -311,12 +338,7 @@ func buildTrie(path string) (*patriciatrie.Trie, BuildStats, error) {
 			continue
 		}
 
-		err := trie.Insert(word)
-		if err != nil {
-			return nil, BuildStats{}, fmt.Errorf("failed to insert word '%s': %w", word, err)
-		}
-
-		lineCount++
+		words = append(words, word)
 	}
 
 	err = scanner.Err()
@@ -324,6 +346,19 @@ func buildTrie(path string) (*patriciatrie.Trie, BuildStats, error) {
 		return nil, BuildStats{}, fmt.Errorf("error reading file: %w", err)
 	}
 
+	// ソート済みのワードリスト（セットアップスクリプトはバイト順で出力）はそのまま一括構築する
+	// 他の手段で用意したリストはバイト順に並んでいない場合があるため、その場合のみ並べ直す
+	if !slices.IsSorted(words) {
+		slices.Sort(words)
+	}
+
+	words = slices.Compact(words)
+
+	trie, err := patriciatrie.FromSorted[any](words)
+	if err != nil {
+		return nil, BuildStats{}, fmt.Errorf("failed to build trie: %w", err)
+	}
+
 	buildTime := time.Since(start)
 	
 	// ガベージコレクションを実行してより正確なメモリ測定を行う
@@ -335,7 +370,7 @@ func buildTrie(path string) (*patriciatrie.Trie, BuildStats, error) {
 	// メモリ使用量の差分を計算（ヒープ上のアロケートされたバイト数）
 	memoryUsed := memAfter.HeapAlloc - memBefore.HeapAlloc
 	
-	fmt.Printf("%s Loaded %d words from %s\n", yellow("📚"), lineCount, path)
+	fmt.Printf("%s Loaded %d words from %s\n", yellow("📚"), len(words), path)
 
 	return trie, BuildStats{
 		Duration:   buildTime,
@@ -343,23 +378,110 @@ func buildTrie(path string) (*patriciatrie.Trie, BuildStats, error) {
 	}, nil
 }
 
+// loadTrie はsaveTrieで保存したトライを読み込む
+func loadTrie(path string) (*patriciatrie.Trie[any], BuildStats, error) {
+	runtime.GC()
+
+	var memBefore runtime.MemStats
+	runtime.ReadMemStats(&memBefore)
+
+	start := time.Now()
+
+	file, err := os.Open(path) // #nosec G304 - ユーザーがコマンドライン引数で指定したファイルのパスなので安全
+	if err != nil {
+		return nil, BuildStats{}, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	trie := patriciatrie.New()
+
+	_, err = trie.ReadFrom(file)
+	if err != nil {
+		return nil, BuildStats{}, fmt.Errorf("failed to read trie: %w", err)
+	}
+
+	loadTime := time.Since(start)
+
+	runtime.GC()
+
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+
+	fmt.Printf("%s Loaded %d words from %s\n", yellow("📚"), trie.Len(), path)
+
+	return trie, BuildStats{
+		Duration:   loadTime,
+		MemoryUsed: memAfter.HeapAlloc - memBefore.HeapAlloc,
+	}, nil
+}
+
+// saveTrie はトライをバイナリ形式でファイルに保存
+func saveTrie(trie *patriciatrie.Trie[any], path string) error {
+	file, err := os.Create(path) // #nosec G304 - ユーザーがコマンドライン引数で指定したファイルのパスなので安全
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	written, err := trie.WriteTo(file)
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to write trie: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	fmt.Printf("%s Saved trie to %s (%.2fMB)\n", yellow("💾"), path, float64(written)/bytesPerMB)
+
+	return nil
+}
+
 // searchWithStats は検索を実行し、verboseモード時は統計情報も収集
-func searchWithStats(trie *patriciatrie.Trie, prefix string, verbose bool) ([]string, *Stats) {
-	// TODO: 実際の実装では、patriciatrie側にStatsを収集する機能を追加する必要がある
-	// 現在は基本的な検索のみ実装
-	results := trie.FindByPrefix(prefix)
-	
-	var stats *Stats
-	if verbose {
-		// 仮の統計情報（実際の実装では内部状態から取得）
-		stats = &Stats{
-			NodesVisited: len(prefix) + len(results), // 仮の値
-			MaxDepth:     len(prefix),                 // 仮の値
-			Duration:     0,                           // 後で設定
-		}
+func searchWithStats(trie *patriciatrie.Trie[any], prefix string, verbose bool) ([]string, *Stats) {
+	if !verbose {
+		return trie.FindByPrefix(prefix), nil
+	}
+
+	results, queryStats := trie.FindByPrefixWithStats(prefix)
+
+	return results, &Stats{
+		NodesVisited: queryStats.NodesVisited,
+		MaxDepth:     queryStats.MaxDepth,
+		Duration:     0, // 後で設定
+	}
+}
+
+// showTrieStats はトライの構造統計を表示
+func showTrieStats() {
+	stats := trie.Stats()
+
+	fmt.Printf("%s Nodes: %d, Keys: %d, Max depth: %d, Avg depth: %.2f\n",
+		yellow("[stats]"), stats.Nodes, stats.TerminalNodes, stats.MaxDepth, stats.AvgDepth)
+	fmt.Printf("%s Labels: %.2fMB, Nodes: %.2fMB, Child index: %.2fMB (estimated)\n",
+		yellow("[stats]"),
+		float64(stats.LabelBytes)/bytesPerMB,
+		float64(stats.NodeBytes)/bytesPerMB,
+		float64(stats.ChildIndexBytes)/bytesPerMB)
+	fmt.Printf("%s Label lengths: %s\n", yellow("[stats]"), formatHistogram(stats.LabelLengths))
+	fmt.Printf("%s Branching factors: %s\n", yellow("[stats]"), formatHistogram(stats.Branching))
+}
+
+// formatHistogram はヒストグラムを「値:件数」の昇順で整形
+func formatHistogram(histogram map[int]int) string {
+	buckets := slices.Sorted(maps.Keys(histogram))
+
+	parts := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		parts = append(parts, fmt.Sprintf("%d:%d", bucket, histogram[bucket]))
 	}
 
-	return results, stats
+	return strings.Join(parts, " ")
 }
 
 // loadHistory は履歴ファイルから履歴を読み込み
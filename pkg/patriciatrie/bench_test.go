@@ -16,7 +16,7 @@ func BenchmarkTrie_Insert(b *testing.B) {
 
 	for i := range b.N {
 		key := keys[i%len(keys)]
-		_ = trie.Insert(key)
+		_, _ = trie.Insert(key)
 	}
 }
 
@@ -27,7 +27,7 @@ func BenchmarkTrie_Search(b *testing.B) {
 
 	// 事前にキーを挿入
 	for _, key := range keys {
-		_ = trie.Insert(key)
+		_, _ = trie.Insert(key)
 	}
 
 	b.ResetTimer()
@@ -50,7 +50,7 @@ func BenchmarkTrie_InsertAndSearch(b *testing.B) {
 	for i := range b.N {
 		key := keys[i%len(keys)]
 		if i%2 == 0 {
-			_ = trie.Insert(key)
+			_, _ = trie.Insert(key)
 		} else {
 			_ = trie.Search(key)
 		}
@@ -71,7 +71,7 @@ func BenchmarkTrie_MemoryUsage(b *testing.B) {
 			for range b.N {
 				trie := New()
 				for _, key := range keys {
-					_ = trie.Insert(key)
+					_, _ = trie.Insert(key)
 				}
 			}
 		})
@@ -89,7 +89,7 @@ func BenchmarkTrie_PrefixLength(b *testing.B) {
 
 			// 事前にキーを挿入
 			for _, key := range keys {
-				_ = trie.Insert(key)
+				_, _ = trie.Insert(key)
 			}
 
 			b.ResetTimer()
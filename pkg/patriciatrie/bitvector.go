@@ -0,0 +1,190 @@
+package patriciatrie
+
+import (
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+const (
+	// bitBlockWords 累積ランクを保持するブロックあたりの64ビット語の数（512ビット）
+	bitBlockWords = 8
+	bitBlockSize  = bitBlockWords * 64
+
+	// bitSelectSample selectの探索範囲を絞るために位置を記録する間隔（1または0の個数）
+	bitSelectSample = 512
+
+	// bitVectorMaxLength ブロックの累積ランクをuint32で表せるビット数の上限（32ビット環境のintでは表せないためuint64）
+	bitVectorMaxLength uint64 = 1<<32 - 1
+)
+
+// bitVector rank/selectに答える追記専用のビット列
+// 512ビットごとの累積ランクと、512個ごとの1と0の位置を含むブロックの番号を補助索引として持つ
+type bitVector struct {
+	words  []uint64
+	length int
+
+	// ranks[b] ブロックbより前にある1の数（末尾に全体の1の数を含む）
+	ranks []uint32
+
+	// select1Hints[k] (k*bitSelectSample)番目の1を含むブロックの番号（select0Hintsは0について同様）
+	select1Hints []uint32
+	select0Hints []uint32
+}
+
+// push ビット列の末尾にビットを追加
+func (v *bitVector) push(bit bool) {
+	if v.length%64 == 0 {
+		v.words = append(v.words, 0)
+	}
+
+	if bit {
+		v.words[v.length/64] |= 1 << (v.length % 64)
+	}
+
+	v.length++
+}
+
+// get 位置iのビットを取得
+func (v *bitVector) get(i int) bool {
+	return v.words[i/64]&(1<<(i%64)) != 0
+}
+
+// buildIndex 追加を終えたビット列のrank/select用の補助索引を作成
+func (v *bitVector) buildIndex() {
+	// 追記のために確保した余分な容量を解放
+	v.words = slices.Clone(v.words)
+
+	blocks := (len(v.words) + bitBlockWords - 1) / bitBlockWords
+	v.ranks = make([]uint32, blocks+1)
+	v.select1Hints = v.select1Hints[:0]
+	v.select0Hints = v.select0Hints[:0]
+
+	ones := 0
+
+	for b := range blocks {
+		v.ranks[b] = uint32(ones) //nolint:gosec // 長さはbitVectorMaxLength以下
+
+		zeros := b*bitBlockSize - ones
+		blockOnes := 0
+
+		for _, word := range v.words[b*bitBlockWords : min((b+1)*bitBlockWords, len(v.words))] {
+			blockOnes += bits.OnesCount64(word)
+		}
+
+		blockZeros := min(bitBlockSize, v.length-b*bitBlockSize) - blockOnes
+
+		// このブロックに入る標本（bitSelectSampleの倍数番目の1と0）を記録
+		for len(v.select1Hints)*bitSelectSample < ones+blockOnes {
+			v.select1Hints = append(v.select1Hints, uint32(b)) //nolint:gosec // ブロック数はuint32に収まる
+		}
+
+		for len(v.select0Hints)*bitSelectSample < zeros+blockZeros {
+			v.select0Hints = append(v.select0Hints, uint32(b)) //nolint:gosec // ブロック数はuint32に収まる
+		}
+
+		ones += blockOnes
+	}
+
+	v.ranks[blocks] = uint32(ones) //nolint:gosec // 長さはbitVectorMaxLength以下
+}
+
+// rank1 位置iより前（[0, i)）にある1の数
+func (v *bitVector) rank1(i int) int {
+	b := i / bitBlockSize
+	rank := int(v.ranks[b])
+
+	w := b * bitBlockWords
+	for ; w < i/64; w++ {
+		rank += bits.OnesCount64(v.words[w])
+	}
+
+	if i%64 != 0 {
+		rank += bits.OnesCount64(v.words[w] & (1<<(i%64) - 1))
+	}
+
+	return rank
+}
+
+// select1 k番目（0始まり）の1の位置
+func (v *bitVector) select1(k int) int {
+	lo, hi := v.selectRange(v.select1Hints, k)
+
+	// 前にある1の数がk以下となる最後のブロック
+	b := lo + sort.Search(hi-lo, func(j int) bool {
+		return int(v.ranks[lo+j+1]) > k
+	})
+
+	remaining := k - int(v.ranks[b])
+
+	for w := b * bitBlockWords; ; w++ {
+		count := bits.OnesCount64(v.words[w])
+		if remaining < count {
+			return w*64 + selectInWord(v.words[w], remaining)
+		}
+
+		remaining -= count
+	}
+}
+
+// select0 k番目（0始まり）の0の位置
+func (v *bitVector) select0(k int) int {
+	lo, hi := v.selectRange(v.select0Hints, k)
+
+	// 前にある0の数がk以下となる最後のブロック
+	b := lo + sort.Search(hi-lo, func(j int) bool {
+		return (lo+j+1)*bitBlockSize-int(v.ranks[lo+j+1]) > k
+	})
+
+	remaining := k - (b*bitBlockSize - int(v.ranks[b]))
+
+	for w := b * bitBlockWords; ; w++ {
+		count := 64 - bits.OnesCount64(v.words[w])
+		if remaining < count {
+			return w*64 + selectInWord(^v.words[w], remaining)
+		}
+
+		remaining -= count
+	}
+}
+
+// selectRange 標本からk番目のビットを含むブロックの候補範囲[lo, hi]を求める
+func (v *bitVector) selectRange(hints []uint32, k int) (int, int) {
+	sample := k / bitSelectSample
+	lo := int(hints[sample])
+	hi := len(v.ranks) - 2
+
+	if sample+1 < len(hints) {
+		hi = int(hints[sample+1])
+	}
+
+	return lo, hi
+}
+
+// nextZero 位置i以降で最初の0の位置（その位置までに0があることは呼び出し側が保証する）
+func (v *bitVector) nextZero(i int) int {
+	w := i / 64
+	if zeros := ^v.words[w] >> (i % 64); zeros != 0 {
+		return i + bits.TrailingZeros64(zeros)
+	}
+
+	for w++; ; w++ {
+		if zeros := ^v.words[w]; zeros != 0 {
+			return w*64 + bits.TrailingZeros64(zeros)
+		}
+	}
+}
+
+// sizeInBytes ビット列と補助索引の大きさ（バイト数）
+func (v *bitVector) sizeInBytes() int {
+	return len(v.words)*8 + (len(v.ranks)+len(v.select1Hints)+len(v.select0Hints))*4
+}
+
+// selectInWord 語wのk番目（0始まり）の1のビット位置
+func selectInWord(w uint64, k int) int {
+	for range k {
+		w &= w - 1
+	}
+
+	return bits.TrailingZeros64(w)
+}
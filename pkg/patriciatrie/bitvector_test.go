@@ -0,0 +1,45 @@
+package patriciatrie
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitVector_RankSelect(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+
+	// 疎・密・偏りのあるビット列で、ブロックと標本の境界をまたぐ位置を確認
+	for _, density := range []float64{0.01, 0.5, 0.99} {
+		var v bitVector
+
+		expected := make([]bool, 5000)
+		for i := range expected {
+			expected[i] = rng.Float64() < density
+			v.push(expected[i])
+		}
+
+		v.buildIndex()
+
+		ones, zeros := 0, 0
+
+		for i, bit := range expected {
+			assert.Equal(t, bit, v.get(i), "get(%d)", i)
+			assert.Equal(t, ones, v.rank1(i), "rank1(%d)", i)
+
+			if bit {
+				assert.Equal(t, i, v.select1(ones), "select1(%d)", ones)
+				ones++
+			} else {
+				assert.Equal(t, i, v.select0(zeros), "select0(%d)", zeros)
+				assert.Equal(t, i, v.nextZero(i), "nextZero(%d)", i)
+				zeros++
+			}
+		}
+
+		assert.Equal(t, ones, v.rank1(len(expected)), density)
+	}
+}
@@ -0,0 +1,124 @@
+package patriciatrie
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// ErrUnsortedInput 一括構築の入力キーがバイト順の昇順に並んでいない
+var ErrUnsortedInput = errors.New("patriciatrie: keys are not in ascending order")
+
+// buildFrame 構築中の最右経路上のノードと、根からそのノードまでのキーの長さ
+// 子ノードはchildrenの位置childStart以降に溜め、ノードの確定時に必要な大きさの索引へまとめて移す
+type buildFrame[V any] struct {
+	node       *Node[V]
+	keyLen     int
+	childStart int
+}
+
+// sortedBuilder 昇順のキーからトライを組み立てる途中状態
+type sortedBuilder[V any] struct {
+	trie     *Trie[V]
+	stack    []buildFrame[V]
+	children []*Node[V]
+}
+
+// FromSorted バイト順の昇順に並んだキーのスライスからパトリシアトライを一括構築
+func FromSorted[V any](keys []string) (*Trie[V], error) {
+	return BuildSorted[V](slices.Values(keys))
+}
+
+// BuildSorted バイト順の昇順に並んだキーの列から、ノードの分割なしに1回の走査でパトリシアトライを構築
+// 連続する重複キーは1つにまとめ、昇順でないキーが現れた場合はErrUnsortedInputを返す（各キーの値はVのゼロ値）
+func BuildSorted[V any](keys iter.Seq[string]) (*Trie[V], error) {
+	b := &sortedBuilder[V]{trie: NewTrie[V]()}
+
+	// 直前のキーの経路（最右経路）のみを保持し、以降のキーが通らなくなったノードから確定させる
+	b.stack = append(b.stack, buildFrame[V]{node: b.trie.root})
+	prev := ""
+	index := 0
+
+	for key := range keys {
+		if index > 0 && key < prev {
+			return nil, fmt.Errorf("%w: key %d %q follows %q", ErrUnsortedInput, index, key, prev)
+		}
+
+		index++
+
+		// 直前のキーと共通するプレフィックスより深いノードを確定
+		commonLen := b.trie.findCommonPrefixLength(prev, key)
+		for len(b.stack) > 1 && b.stack[len(b.stack)-2].keyLen >= commonLen {
+			b.finish()
+		}
+
+		// 共通プレフィックスがエッジラベルの途中で終わる場合、そこで分岐する中間ノードを作成
+		if b.stack[len(b.stack)-1].keyLen > commonLen {
+			b.split(commonLen)
+		}
+
+		if commonLen == len(key) {
+			// 直前と同じキー（最初のキーが空文字列の場合を含む）
+			if index == 1 {
+				b.trie.root.isEndOfKey = true
+				b.trie.root.count = 1
+			}
+
+			continue
+		}
+
+		// 昇順のため新しい子ノードは常に親ノードの子の末尾に並ぶ
+		leaf := NewNode[V](key[commonLen:])
+		leaf.isEndOfKey = true
+		leaf.count = 1
+		b.push(leaf, len(key))
+
+		prev = key
+	}
+
+	for len(b.stack) > 0 {
+		b.finish()
+	}
+
+	return b.trie, nil
+}
+
+// push 最右経路の末尾のノードに子ノードを追加し、経路を延ばす
+func (b *sortedBuilder[V]) push(node *Node[V], keyLen int) {
+	b.children = append(b.children, node)
+	b.stack = append(b.stack, buildFrame[V]{node: node, keyLen: keyLen, childStart: len(b.children)})
+}
+
+// split 最右経路の末尾のノードのラベルをキーの長さkeyLenの位置で分割し、中間ノードで置き換える
+// 分割されたノードの部分木はこれ以上変化しないため、確定させて中間ノードの子にする
+func (b *sortedBuilder[V]) split(keyLen int) {
+	b.finish()
+
+	parent := b.stack[len(b.stack)-1]
+	child := b.children[len(b.children)-1]
+	splitAt := keyLen - parent.keyLen
+
+	intermediate := NewNode[V](child.label[:splitAt])
+	child.label = child.label[splitAt:]
+
+	// 親ノードの子の末尾にある分割元のノードを中間ノードに差し替え、分割元のノードを中間ノードの最初の子にする
+	b.children = b.children[:len(b.children)-1]
+	b.push(intermediate, keyLen)
+	b.children = append(b.children, child)
+}
+
+// finish 最右経路の末尾のノードを確定し、溜めた子ノードを設定して部分木のキー数を数える
+// 子ノードはすべて先に確定しているため、部分木のキー数は自身と子ノードの合計になる
+func (b *sortedBuilder[V]) finish() {
+	last := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+
+	children := b.children[last.childStart:]
+	for _, child := range children {
+		last.node.count += child.count
+	}
+
+	last.node.setChildren(children)
+	b.children = b.children[:last.childStart]
+}
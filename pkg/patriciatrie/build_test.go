@@ -0,0 +1,128 @@
+package patriciatrie
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSorted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{"空の入力", nil},
+		{"空文字列のみ", []string{""}},
+		{"空文字列を含む", []string{"", "a", "ab"}},
+		{"プレフィックスの連鎖", []string{"a", "ab", "abc", "abcd"}},
+		{"ラベル途中での分岐", []string{"abcx", "abcy", "abd", "b"}},
+		{"分岐後に短いキー", []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}},
+		{"重複を含む", []string{"cat", "cat", "cats", "dog", "dog"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trie, err := FromSorted[any](tt.keys)
+			require.NoError(t, err)
+			require.NoError(t, trie.Validate())
+
+			want := slices.Compact(slices.Clone(tt.keys))
+			assert.Equal(t, want, slices.Collect(trie.Keys()))
+			assert.Equal(t, len(want), trie.Len())
+		})
+	}
+}
+
+func TestFromSorted_MatchesInsert(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(1))
+
+	// 短いアルファベットで共通プレフィックスと分岐を多く作る
+	keys := make([]string, 5000)
+	for i := range keys {
+		key := make([]byte, rng.Intn(8))
+		for j := range key {
+			key[j] = "abc"[rng.Intn(3)]
+		}
+
+		keys[i] = string(key)
+	}
+
+	inserted := New()
+	for _, key := range keys {
+		_, err := inserted.Insert(key)
+		require.NoError(t, err)
+	}
+
+	slices.Sort(keys)
+
+	built, err := FromSorted[any](keys)
+	require.NoError(t, err)
+	require.NoError(t, built.Validate())
+
+	assert.Equal(t, slices.Collect(inserted.Keys()), slices.Collect(built.Keys()))
+	assert.Equal(t, inserted.Stats().Nodes, built.Stats().Nodes)
+
+	// 構築後も通常の更新ができる
+	_, err = built.Insert("abcabcabc")
+	require.NoError(t, err)
+
+	_, err = built.Delete(keys[len(keys)/2])
+	require.NoError(t, err)
+	require.NoError(t, built.Validate())
+}
+
+func TestBuildSorted_Unsorted(t *testing.T) {
+	t.Parallel()
+
+	_, err := FromSorted[any]([]string{"apple", "banana", "avocado"})
+	require.ErrorIs(t, err, ErrUnsortedInput)
+	assert.Contains(t, err.Error(), `"avocado"`)
+
+	// 空文字列は先頭にしか置けない
+	_, err = FromSorted[any]([]string{"a", ""})
+	require.ErrorIs(t, err, ErrUnsortedInput)
+}
+
+func TestBuildSorted_Seq(t *testing.T) {
+	t.Parallel()
+
+	source, err := FromSorted[any]([]string{"car", "cat", "cats", "dog"})
+	require.NoError(t, err)
+
+	// 別のトライの昇順の列挙からそのまま構築できる
+	trie, err := BuildSorted[any](source.Keys())
+	require.NoError(t, err)
+	require.NoError(t, trie.Validate())
+
+	assert.Equal(t, []string{"cat", "cats"}, trie.FindByPrefix("cat"))
+	assert.Equal(t, 4, trie.Len())
+}
+
+func TestFromSorted_Typed(t *testing.T) {
+	t.Parallel()
+
+	trie, err := FromSorted[int]([]string{"car", "cat", "cats"})
+	require.NoError(t, err)
+
+	// 構築直後の値はゼロ値で、通常の更新で値を設定できる
+	value, found := trie.Get("cat")
+	assert.True(t, found)
+	assert.Zero(t, value)
+
+	_, err = trie.Put("cat", 42)
+	require.NoError(t, err)
+
+	value, found = trie.Get("cat")
+	assert.True(t, found)
+	assert.Equal(t, 42, value)
+	assert.Equal(t, 3, trie.Len())
+}
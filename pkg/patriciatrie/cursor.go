@@ -0,0 +1,235 @@
+package patriciatrie
+
+// Cursor トライのキーをバイト順に双方向へ辿るカーソル
+// 圧縮されたノード構造を直接辿り、キーはエッジラベルの追加・削除で差分更新する
+// カーソルの使用中にトライを変更した場合の動作は未定義
+type Cursor[V any] struct {
+	// 根から現在のノードまでの経路
+	stack []cursorFrame[V]
+
+	// 現在のノードまでのエッジラベルを連結したキー
+	key []byte
+
+	trie *Trie[V]
+}
+
+// cursorFrame カーソルの経路上の1ノード
+type cursorFrame[V any] struct {
+	node *Node[V]
+
+	// 次のフレームとして降下している子ノードの位置（先頭バイトの昇順、-1はノード自身）
+	index int
+}
+
+// Cursor 位置が未設定のカーソルを作成（First, Last, Seekで位置を設定）
+func (t *Trie[V]) Cursor() *Cursor[V] {
+	return &Cursor[V]{trie: t}
+}
+
+// Valid カーソルがキーを指しているかを取得
+func (c *Cursor[V]) Valid() bool {
+	return len(c.stack) > 0
+}
+
+// Key カーソルが指すキーを取得（無効な場合は空文字列）
+func (c *Cursor[V]) Key() string {
+	if !c.Valid() {
+		return ""
+	}
+
+	return string(c.key)
+}
+
+// Value カーソルが指すキーの値を取得（無効な場合はゼロ値）
+func (c *Cursor[V]) Value() V {
+	if !c.Valid() {
+		var zero V
+
+		return zero
+	}
+
+	return c.top().node.value
+}
+
+// First 最小のキーに移動（キーが存在しない場合はfalse）
+func (c *Cursor[V]) First() bool {
+	c.reset()
+
+	return c.leftmost()
+}
+
+// Last 最大のキーに移動（キーが存在しない場合はfalse）
+func (c *Cursor[V]) Last() bool {
+	c.reset()
+
+	return c.rightmost()
+}
+
+// Seek key以上の最小のキーに移動（該当するキーが存在しない場合はfalse）
+func (c *Cursor[V]) Seek(key string) bool {
+	c.reset()
+
+	consumed := 0
+
+	for consumed < len(key) {
+		remaining := key[consumed:]
+		node := c.top().node
+
+		// remainingの先頭バイト以上で始まる最初の子ノードを探す
+		index, _ := node.childIndex(remaining[0])
+
+		// すべての子ノードがkeyより前に並ぶ場合、この部分木の次へ進む
+		if index == node.ChildrenCount() {
+			return c.nextSubtree()
+		}
+
+		child := node.childAt(index)
+		c.push(index)
+
+		if child.label[0] > remaining[0] {
+			return c.leftmost()
+		}
+
+		commonLen := c.trie.findCommonPrefixLength(child.label, remaining)
+
+		switch {
+		case commonLen == len(child.label):
+			consumed += commonLen
+		case commonLen == len(remaining) || child.label[commonLen] > remaining[commonLen]:
+			// 子ノードの部分木全体がkeyより後に並ぶ
+			return c.leftmost()
+		default:
+			// 子ノードの部分木全体がkeyより前に並ぶ
+			return c.nextSubtree()
+		}
+	}
+
+	// keyと一致するノードに到達した場合、その部分木の最小のキーが答え
+	return c.leftmost()
+}
+
+// Next 次のキーに移動（次のキーが存在しない場合はfalseとなり、カーソルは無効になる）
+func (c *Cursor[V]) Next() bool {
+	if !c.Valid() {
+		return false
+	}
+
+	// 子孫のキーは自身より後に並ぶ
+	if !c.top().node.IsLeaf() {
+		c.push(0)
+
+		return c.leftmost()
+	}
+
+	return c.nextSubtree()
+}
+
+// Prev 前のキーに移動（前のキーが存在しない場合はfalseとなり、カーソルは無効になる）
+func (c *Cursor[V]) Prev() bool {
+	for c.Valid() {
+		c.pop()
+
+		if !c.Valid() {
+			return false
+		}
+
+		frame := c.top()
+
+		// 前の兄弟の部分木があれば、その最大のキーが答え
+		if frame.index > 0 {
+			frame.index--
+			c.push(frame.index)
+
+			return c.rightmost()
+		}
+
+		// 自身のキーは子孫のキーより前に並ぶ
+		frame.index = -1
+		if frame.node.isEndOfKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reset 根ノードのみを経路に持つ状態に戻す
+func (c *Cursor[V]) reset() {
+	c.stack = c.stack[:0]
+	c.key = c.key[:0]
+	c.pushNode(c.trie.root)
+}
+
+// top 経路の末尾のフレームを取得
+func (c *Cursor[V]) top() *cursorFrame[V] {
+	return &c.stack[len(c.stack)-1]
+}
+
+// push 末尾のフレームのindex番目の子ノードへ降下
+func (c *Cursor[V]) push(index int) {
+	frame := c.top()
+	frame.index = index
+	c.pushNode(frame.node.childAt(index))
+}
+
+// pushNode ノードを経路に追加し、キーにラベルを追加
+func (c *Cursor[V]) pushNode(node *Node[V]) {
+	c.key = append(c.key, node.label...)
+	c.stack = append(c.stack, cursorFrame[V]{node: node, index: -1})
+}
+
+// pop 末尾のフレームを取り除き、キーからラベルを取り除く
+func (c *Cursor[V]) pop() {
+	c.key = c.key[:len(c.key)-len(c.top().node.label)]
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+// leftmost 末尾のノードを根とする部分木の最小のキーへ移動
+func (c *Cursor[V]) leftmost() bool {
+	for !c.top().node.isEndOfKey {
+		if c.top().node.IsLeaf() {
+			// 空のトライの根ノード
+			c.stack = c.stack[:0]
+
+			return false
+		}
+
+		c.push(0)
+	}
+
+	return true
+}
+
+// rightmost 末尾のノードを根とする部分木の最大のキーへ移動
+func (c *Cursor[V]) rightmost() bool {
+	for !c.top().node.IsLeaf() {
+		c.push(c.top().node.ChildrenCount() - 1)
+	}
+
+	if !c.top().node.isEndOfKey {
+		// 空のトライの根ノード
+		c.stack = c.stack[:0]
+
+		return false
+	}
+
+	return true
+}
+
+// nextSubtree 末尾のノードを根とする部分木より後の最小のキーへ移動
+func (c *Cursor[V]) nextSubtree() bool {
+	for {
+		c.pop()
+
+		if !c.Valid() {
+			return false
+		}
+
+		frame := c.top()
+		if frame.index+1 < frame.node.ChildrenCount() {
+			c.push(frame.index + 1)
+
+			return c.leftmost()
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package patriciatrie
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_NextPrev(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"dogs", "cat", "", "cats", "dog", "ca", "catalog", "b"}
+	trie := newIntTrie(t, keys...)
+	sorted := slices.Sorted(slices.Values(keys))
+
+	// 昇順の走査
+	var forward []string
+
+	cursor := trie.Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		expected, _ := trie.Get(cursor.Key())
+		assert.Equal(t, expected, cursor.Value())
+
+		forward = append(forward, cursor.Key())
+	}
+
+	assert.Equal(t, sorted, forward)
+	assert.False(t, cursor.Valid())
+
+	// 降順の走査
+	var backward []string
+	for ok := cursor.Last(); ok; ok = cursor.Prev() {
+		backward = append(backward, cursor.Key())
+	}
+
+	slices.Reverse(sorted)
+	assert.Equal(t, sorted, backward)
+}
+
+func TestCursor_ChangeDirection(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "a", "ab", "abc", "b")
+	cursor := trie.Cursor()
+
+	require.True(t, cursor.Seek("ab"))
+	assert.Equal(t, "ab", cursor.Key())
+
+	require.True(t, cursor.Next())
+	assert.Equal(t, "abc", cursor.Key())
+
+	require.True(t, cursor.Prev())
+	assert.Equal(t, "ab", cursor.Key())
+
+	require.True(t, cursor.Prev())
+	assert.Equal(t, "a", cursor.Key())
+
+	assert.False(t, cursor.Prev())
+	assert.False(t, cursor.Valid())
+	assert.Empty(t, cursor.Key())
+	assert.Equal(t, 0, cursor.Value())
+}
+
+func TestCursor_Seek(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "b", "ca", "cat", "catalog", "cats", "dog", "dogs")
+
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+		ok       bool
+	}{
+		{"存在するキー", "cat", "cat", true},
+		{"空文字列", "", "b", true},
+		{"すべてのキーより前", "a", "b", true},
+		{"すべてのキーより後", "z", "", false},
+		{"ラベル途中で大きい", "catb", "cats", true},
+		{"ラベル途中で小さい", "cataa", "catalog", true},
+		{"ラベルの途中で終わる", "cata", "catalog", true},
+		{"部分木の後", "catz", "dog", true},
+		{"最後のキーより長い", "dogsled", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor := trie.Cursor()
+			assert.Equal(t, tt.ok, cursor.Seek(tt.key))
+			assert.Equal(t, tt.expected, cursor.Key())
+		})
+	}
+}
+
+func TestCursor_SeekRandom(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range generateRandomKeys(500) {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	sorted := slices.Collect(trie.Keys())
+	cursor := trie.Cursor()
+
+	for _, target := range generateRandomKeys(200) {
+		i := sort.SearchStrings(sorted, target)
+
+		if i == len(sorted) {
+			assert.False(t, cursor.Seek(target))
+
+			continue
+		}
+
+		require.True(t, cursor.Seek(target), "seek %q", target)
+		assert.Equal(t, sorted[i], cursor.Key())
+
+		if i > 0 {
+			require.True(t, cursor.Prev())
+			assert.Equal(t, sorted[i-1], cursor.Key())
+		}
+	}
+}
+
+func TestCursor_EmptyTrie(t *testing.T) {
+	t.Parallel()
+
+	cursor := New().Cursor()
+
+	assert.False(t, cursor.First())
+	assert.False(t, cursor.Last())
+	assert.False(t, cursor.Seek("a"))
+	assert.False(t, cursor.Next())
+	assert.False(t, cursor.Prev())
+}
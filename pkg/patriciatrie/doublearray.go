@@ -0,0 +1,475 @@
+package patriciatrie
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ErrDoubleArrayTooLarge ダブル配列の要素数がint32で表せる範囲を超える
+var ErrDoubleArrayTooLarge = errors.New("patriciatrie: double-array exceeds 2^31 units")
+
+const (
+	// daTerminal キーの終端を表す遷移コード（バイトbの遷移コードはb+1）
+	daTerminal = 0
+
+	// daNoLabel 子ノードまたは次の兄弟ノードがないことを表す遷移コード
+	daNoLabel = math.MaxUint16
+
+	// daDenseRatio 探索開始位置より後ろの使用済み要素がこの割合を超えたら、開始位置を進める
+	daDenseRatio = 0.95
+)
+
+// daUnit ダブル配列の要素
+// baseは子ノードの配置の基準位置（終端の遷移先では値）、checkは親ノードの位置（未使用の要素は-1）
+type daUnit struct {
+	base  int32
+	check int32
+}
+
+// daLink 予測検索で子ノードを昇順に辿るための遷移コード
+// childは最初の子ノード、siblingは同じ親を持つ次の子ノードの遷移コード
+type daLink struct {
+	child   uint16
+	sibling uint16
+}
+
+// DoubleArray キーをバイト単位で遷移させるダブル配列トライ（int32の値を持つ読み取り専用の辞書）
+// 遷移は配列の添字計算と親の照合のみで行い、完全一致・共通プレフィックス検索・予測検索に答える
+type DoubleArray struct {
+	units []daUnit
+	links []daLink
+	count int
+}
+
+// daTask 配置待ちのノードと、そのノード以下に入るキーの範囲[lo, hi)、ノードまでのキーの長さ
+type daTask struct {
+	node   int
+	lo, hi int
+	depth  int
+}
+
+// daBuilder 昇順のキーからダブル配列を組み立てる途中状態
+type daBuilder struct {
+	keys   []string
+	values []int32
+	units  []daUnit
+	links  []daLink
+	used   []bool
+
+	// 空き要素の探索開始位置
+	head int
+
+	// ノードごとに使い回す子ノードの遷移コードとキーの範囲の先頭
+	codes  []int
+	starts []int
+}
+
+// BuildDoubleArray バイト順の昇順に並んだキーと値の列からダブル配列を構築
+// 連続する重複キーは最初の値を採用し、昇順でないキーが現れた場合はErrUnsortedInputを返す
+func BuildDoubleArray(entries iter.Seq2[string, int32]) (*DoubleArray, error) {
+	var (
+		keys   []string
+		values []int32
+	)
+
+	for key, value := range entries {
+		if len(keys) > 0 {
+			prev := keys[len(keys)-1]
+			if key < prev {
+				return nil, fmt.Errorf("%w: key %d %q follows %q", ErrUnsortedInput, len(keys), key, prev)
+			}
+
+			if key == prev {
+				continue
+			}
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	return buildDoubleArray(keys, values)
+}
+
+// DoubleArrayFromSorted バイト順の昇順に並んだキーからダブル配列を構築
+// 各キーの値は重複を除いた昇順での番号（0始まり）
+func DoubleArrayFromSorted(keys []string) (*DoubleArray, error) {
+	return BuildDoubleArray(func(yield func(string, int32) bool) {
+		id := int32(-1)
+
+		for i, key := range keys {
+			if i == 0 || key != keys[i-1] {
+				id++
+			}
+
+			if !yield(key, id) {
+				return
+			}
+		}
+	})
+}
+
+// LoadDoubleArray 1行に1語の単語リスト（scripts/setup_benchmark_data.shの出力形式）からダブル配列を構築
+// 各行の前後の空白（CRLFのCRを含む）は取り除き、空行は無視する。単語はバイト順の昇順に並んでいる必要があり、値は昇順での単語の番号
+func LoadDoubleArray(r io.Reader) (*DoubleArray, error) {
+	var words []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read words: %w", err)
+	}
+
+	return DoubleArrayFromSorted(words)
+}
+
+// ToDoubleArray トライのキーと、valueで変換した値からダブル配列を構築
+// valueがnilの場合、各キーの値はバイト順の昇順での番号（Rankと一致）
+func (t *Trie[V]) ToDoubleArray(value func(key string, value V) int32) (*DoubleArray, error) {
+	keys := make([]string, 0, t.Len())
+	values := make([]int32, 0, t.Len())
+
+	t.Walk(Ascending, func(key string, v V) bool {
+		if value != nil {
+			values = append(values, value(key, v))
+		} else {
+			values = append(values, int32(len(keys))) //nolint:gosec // 要素数の上限は構築時に確認
+		}
+
+		keys = append(keys, key)
+
+		return true
+	})
+
+	return buildDoubleArray(keys, values)
+}
+
+// buildDoubleArray 重複のない昇順のキーと値からダブル配列を構築
+// キーの範囲を共通の先頭バイトごとに分けながら、根から順に子ノードの遷移コードがすべて空いている位置へ配置する
+func buildDoubleArray(keys []string, values []int32) (*DoubleArray, error) {
+	b := &daBuilder{keys: keys, values: values}
+	b.grow(1)
+	b.used[0] = true
+
+	stack := []daTask{{node: 0, lo: 0, hi: len(keys)}}
+
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if task.lo == task.hi {
+			continue
+		}
+
+		b.groupChildren(task)
+
+		base := b.findBase()
+		if base+b.codes[len(b.codes)-1] >= math.MaxInt32 {
+			return nil, fmt.Errorf("%w: %d keys", ErrDoubleArrayTooLarge, len(keys))
+		}
+
+		b.units[task.node].base = int32(base)         //nolint:gosec // 上限を確認済み
+		b.links[task.node].child = uint16(b.codes[0]) //nolint:gosec // 遷移コードは256以下
+
+		for i, code := range b.codes {
+			child := base + code
+			b.used[child] = true
+			b.units[child].check = int32(task.node) //nolint:gosec // 上限を確認済み
+
+			if i+1 < len(b.codes) {
+				b.links[child].sibling = uint16(b.codes[i+1]) //nolint:gosec // 遷移コードは256以下
+			}
+
+			// 終端の遷移先は子ノードを持たないため、baseに値を格納する
+			if code == daTerminal {
+				b.units[child].base = b.values[b.starts[i]]
+
+				continue
+			}
+
+			stack = append(stack, daTask{node: child, lo: b.starts[i], hi: b.starts[i+1], depth: task.depth + 1})
+		}
+	}
+
+	// 最後に使用した要素より後ろを切り詰める
+	size := len(b.used)
+	for size > 1 && !b.used[size-1] {
+		size--
+	}
+
+	return &DoubleArray{
+		units: slices.Clone(b.units[:size]),
+		links: slices.Clone(b.links[:size]),
+		count: len(keys),
+	}, nil
+}
+
+// groupChildren ノード以下のキーを深さdepthのバイトごとに分け、子ノードの遷移コードとキーの範囲を求める
+// キーは昇順のため、遷移コードは昇順に並び、終端（キーの長さがdepth）は範囲の先頭のキーのみ
+func (b *daBuilder) groupChildren(task daTask) {
+	b.codes = b.codes[:0]
+	b.starts = b.starts[:0]
+
+	for i := task.lo; i < task.hi; i++ {
+		code := daTerminal
+		if len(b.keys[i]) > task.depth {
+			code = int(b.keys[i][task.depth]) + 1
+		}
+
+		if len(b.codes) == 0 || b.codes[len(b.codes)-1] != code {
+			b.codes = append(b.codes, code)
+			b.starts = append(b.starts, i)
+		}
+	}
+
+	b.starts = append(b.starts, task.hi)
+}
+
+// findBase すべての子ノードの遷移コードcについてbase+cが空いているbaseを探す
+// 先頭付近の使用済み要素を繰り返し調べないよう、ほぼ埋まった区間の後ろから探索を始める
+func (b *daBuilder) findBase() int {
+	first := b.codes[0]
+	pos := max(b.head, first+1) - 1
+	usedCount := 0
+	seenFree := false
+
+	for {
+		pos++
+		b.grow(pos + 1)
+
+		if b.used[pos] {
+			usedCount++
+
+			continue
+		}
+
+		if !seenFree {
+			b.head = pos
+			seenFree = true
+		}
+
+		base := pos - first
+		b.grow(base + b.codes[len(b.codes)-1] + 1)
+
+		fits := true
+
+		for _, code := range b.codes[1:] {
+			if b.used[base+code] {
+				fits = false
+
+				break
+			}
+		}
+
+		if fits {
+			break
+		}
+	}
+
+	if float64(usedCount)/float64(pos-b.head+1) >= daDenseRatio {
+		b.head = pos
+	}
+
+	return pos - first
+}
+
+// grow 配列の長さを少なくともnにする（追加した要素は未使用）
+func (b *daBuilder) grow(n int) {
+	for len(b.used) < n {
+		b.units = append(b.units, daUnit{check: -1})
+		b.links = append(b.links, daLink{child: daNoLabel, sibling: daNoLabel})
+		b.used = append(b.used, false)
+	}
+}
+
+// Len 格納されているキーの数を取得
+func (d *DoubleArray) Len() int {
+	return d.count
+}
+
+// transition ノードsから遷移コードcの子ノードへ遷移
+func (d *DoubleArray) transition(s, c int) (int, bool) {
+	t := int(d.units[s].base) + c
+	if t >= len(d.units) || int(d.units[t].check) != s {
+		return 0, false
+	}
+
+	return t, true
+}
+
+// findNode キーまたはプレフィックスの末尾に対応するノードを取得
+func (d *DoubleArray) findNode(key string) (int, bool) {
+	node := 0
+
+	for i := range len(key) {
+		next, ok := d.transition(node, int(key[i])+1)
+		if !ok {
+			return 0, false
+		}
+
+		node = next
+	}
+
+	return node, true
+}
+
+// Search キーが存在するかを検索
+func (d *DoubleArray) Search(key string) bool {
+	_, found := d.Get(key)
+
+	return found
+}
+
+// Get キーに対応する値を取得
+func (d *DoubleArray) Get(key string) (int32, bool) {
+	node, ok := d.findNode(key)
+	if !ok {
+		return 0, false
+	}
+
+	end, ok := d.transition(node, daTerminal)
+	if !ok {
+		return 0, false
+	}
+
+	return d.units[end].base, true
+}
+
+// LongestPrefixOf sのプレフィックスとなる格納済みキーのうち最長のものと、その値を取得
+func (d *DoubleArray) LongestPrefixOf(s string) (string, int32, bool) {
+	var (
+		value  int32
+		length int
+		found  bool
+	)
+
+	d.visitPrefixesOf(s, func(n int, v int32) bool {
+		length, value, found = n, v, true
+
+		return true
+	})
+
+	return s[:length], value, found
+}
+
+// PrefixesOf sのプレフィックスとなる格納済みキーをすべて短い順に取得（共通プレフィックス検索）
+func (d *DoubleArray) PrefixesOf(s string) []PrefixMatch[int32] {
+	var result []PrefixMatch[int32]
+
+	d.visitPrefixesOf(s, func(n int, value int32) bool {
+		result = append(result, PrefixMatch[int32]{Key: s[:n], Length: n, Value: value})
+
+		return true
+	})
+
+	return result
+}
+
+// PrefixesOfSeq sのプレフィックスとなる格納済みキーと値を短い順に遅延列挙するイテレータを取得
+func (d *DoubleArray) PrefixesOfSeq(s string) iter.Seq2[string, int32] {
+	return func(yield func(string, int32) bool) {
+		d.visitPrefixesOf(s, func(n int, value int32) bool {
+			return yield(s[:n], value)
+		})
+	}
+}
+
+// visitPrefixesOf sのプレフィックスとなる格納済みキーの長さと値を短い順に訪問（falseを返すと中断）
+func (d *DoubleArray) visitPrefixesOf(s string, fn func(n int, value int32) bool) {
+	node := 0
+
+	for i := 0; ; i++ {
+		if end, ok := d.transition(node, daTerminal); ok && !fn(i, d.units[end].base) {
+			return
+		}
+
+		if i == len(s) {
+			return
+		}
+
+		next, ok := d.transition(node, int(s[i])+1)
+		if !ok {
+			return
+		}
+
+		node = next
+	}
+}
+
+// FindByPrefix 指定されたプレフィックスを持つすべてのキーをバイト順の昇順で検索（予測検索）
+func (d *DoubleArray) FindByPrefix(prefix string) []string {
+	var result []string
+
+	for key := range d.WithPrefix(prefix) {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+// WithPrefix 指定されたプレフィックスを持つキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (d *DoubleArray) WithPrefix(prefix string) iter.Seq2[string, int32] {
+	return func(yield func(string, int32) bool) {
+		node, ok := d.findNode(prefix)
+		if !ok {
+			return
+		}
+
+		d.walk(node, prefix, func(key []byte, value int32) bool {
+			return yield(string(key), value)
+		})
+	}
+}
+
+// daFrame 走査中のノードと、次に辿る子ノードの遷移コード、ノードまでのキーの長さ
+type daFrame struct {
+	node   int
+	next   uint16
+	keyLen int
+}
+
+// walk 指定されたノード以下のキーと値を昇順に走査（中断された場合はfalseを返す）
+// 遷移コード0の終端は他の子ノードより先に辿るため、短いキーが先に現れる
+func (d *DoubleArray) walk(node int, prefix string, fn func(key []byte, value int32) bool) bool {
+	key := []byte(prefix)
+	stack := []daFrame{{node: node, next: d.links[node].child, keyLen: len(key)}}
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == daNoLabel {
+			stack = stack[:len(stack)-1]
+
+			continue
+		}
+
+		code := int(top.next)
+		child := int(d.units[top.node].base) + code
+		top.next = d.links[child].sibling
+		key = key[:top.keyLen]
+
+		if code == daTerminal {
+			if !fn(key, d.units[child].base) {
+				return false
+			}
+
+			continue
+		}
+
+		key = append(key, byte(code-1))
+		stack = append(stack, daFrame{node: child, next: d.links[child].child, keyLen: len(key)})
+	}
+
+	return true
+}
@@ -0,0 +1,181 @@
+package patriciatrie
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoubleArrayFromSorted(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"", "car", "cat", "cats", "do", "dog", "東京", "東京", "東京都"}
+
+	da, err := DoubleArrayFromSorted(keys)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, da.Len())
+
+	// 値は重複を除いた昇順での番号
+	for id, key := range slices.Compact(slices.Clone(keys)) {
+		value, found := da.Get(key)
+		assert.True(t, found, key)
+		assert.Equal(t, int32(id), value, key)
+	}
+
+	assert.False(t, da.Search("ca"))
+	assert.False(t, da.Search("catsup"))
+	assert.False(t, da.Search("東"))
+
+	assert.Equal(t, []string{"car", "cat", "cats"}, da.FindByPrefix("ca"))
+	assert.Equal(t, []string{"東京", "東京都"}, da.FindByPrefix("東"))
+	assert.Equal(t, []string{"do", "dog"}, da.FindByPrefix("do"))
+	assert.Empty(t, da.FindByPrefix("x"))
+
+	assert.Equal(t, []PrefixMatch[int32]{
+		{Key: "", Length: 0, Value: 0},
+		{Key: "cat", Length: 3, Value: 2},
+		{Key: "cats", Length: 4, Value: 3},
+	}, da.PrefixesOf("catsup"))
+
+	key, value, found := da.LongestPrefixOf("東京都庁")
+	assert.True(t, found)
+	assert.Equal(t, "東京都", key)
+	assert.Equal(t, int32(7), value)
+}
+
+func TestBuildDoubleArray(t *testing.T) {
+	t.Parallel()
+
+	entries := func(yield func(string, int32) bool) {
+		for _, entry := range []struct {
+			key   string
+			value int32
+		}{{"a", -5}, {"a", 9}, {"ab", 1 << 30}, {"b\x00", 3}, {"b\xff", -1 << 31}} {
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+
+	da, err := BuildDoubleArray(entries)
+	require.NoError(t, err)
+
+	// 重複キーは最初の値を採用し、値には負数とバイト0・0xffを含むキーも使える
+	expected := map[string]int32{"a": -5, "ab": 1 << 30, "b\x00": 3, "b\xff": -1 << 31}
+	for key, want := range expected {
+		value, found := da.Get(key)
+		assert.True(t, found, key)
+		assert.Equal(t, want, value, key)
+	}
+
+	assert.Equal(t, []string{"b\x00", "b\xff"}, da.FindByPrefix("b"))
+	assert.False(t, da.Search("b"))
+}
+
+func TestBuildDoubleArray_Unsorted(t *testing.T) {
+	t.Parallel()
+
+	_, err := DoubleArrayFromSorted([]string{"apple", "banana", "apricot"})
+	require.ErrorIs(t, err, ErrUnsortedInput)
+	assert.Contains(t, err.Error(), `"apricot"`)
+}
+
+func TestDoubleArray_Empty(t *testing.T) {
+	t.Parallel()
+
+	da, err := DoubleArrayFromSorted(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, da.Len())
+	assert.False(t, da.Search(""))
+	assert.False(t, da.Search("a"))
+	assert.Empty(t, da.FindByPrefix(""))
+	assert.Empty(t, da.PrefixesOf("abc"))
+}
+
+func TestLoadDoubleArray(t *testing.T) {
+	t.Parallel()
+
+	// setup_benchmark_data.shと同じくバイト順に並んだ1行1語の単語リスト
+	da, err := LoadDoubleArray(strings.NewReader("すもも\nもも\n\nももの\n東京\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, da.Len())
+
+	value, found := da.Get("ももの")
+	assert.True(t, found)
+	assert.Equal(t, int32(2), value)
+
+	_, err = LoadDoubleArray(strings.NewReader("もも\nすもも\n"))
+	require.ErrorIs(t, err, ErrUnsortedInput)
+
+	// CRLFの改行や前後の空白はREPLの読み込みと同じく取り除く
+	da, err = LoadDoubleArray(strings.NewReader("すもも\r\n もも \r\n\r\nももの\t\r\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"すもも", "もも", "ももの"}, da.FindByPrefix(""))
+}
+
+func TestTrie_ToDoubleArray(t *testing.T) {
+	t.Parallel()
+
+	trie := newRandomIntTrie(t, 1, 3000, "abcde")
+
+	da, err := trie.ToDoubleArray(func(_ string, value int) int32 {
+		return int32(value) //nolint:gosec // テストの値は小さい
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, trie.Len(), da.Len())
+	assert.Equal(t, slices.Collect(trie.Keys()), da.FindByPrefix(""))
+
+	for _, query := range []string{"", "a", "ab", "abc", "e", "eeee", "abcdeabcde", "x"} {
+		wantValue, wantFound := trie.Get(query)
+		gotValue, gotFound := da.Get(query)
+		assert.Equal(t, wantFound, gotFound, query)
+		assert.Equal(t, int32(wantValue), gotValue, query) //nolint:gosec // テストの値は小さい
+
+		assert.Equal(t, trie.FindByPrefix(query), da.FindByPrefix(query), query)
+
+		var want, got []string
+
+		for _, match := range trie.PrefixesOf(query) {
+			want = append(want, match.Key)
+		}
+
+		for _, match := range da.PrefixesOf(query) {
+			got = append(got, match.Key)
+		}
+
+		assert.Equal(t, want, got, query)
+	}
+
+	// 値の変換を省略すると、値は昇順での番号（Rank）
+	ids, err := trie.ToDoubleArray(nil)
+	require.NoError(t, err)
+
+	for key := range trie.Keys() {
+		value, found := ids.Get(key)
+		assert.True(t, found, key)
+		assert.Equal(t, trie.Rank(key), int(value), key)
+	}
+}
+
+//nolint:paralleltest // AllocsPerRun must not run in parallel
+func TestDoubleArray_SearchDoesNotAllocate(t *testing.T) {
+	da, err := DoubleArrayFromSorted([]string{"car", "cat", "cats", "dog"})
+	require.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = da.Search("cats")
+		_, _, _ = da.LongestPrefixOf("category")
+
+		for range da.PrefixesOfSeq("catsup") {
+		}
+	})
+	assert.Zero(t, allocs)
+}
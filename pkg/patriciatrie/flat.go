@@ -0,0 +1,505 @@
+package patriciatrie
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"slices"
+)
+
+// フラット形式（リトルエンディアン、ポインタを含まずファイルをそのままメモリに置いて参照できる）
+//
+//	ヘッダ:     マジック"PTRF"(4) | バージョン(uint16) | 予約(uint16) | ノード数(uint32) | ラベル長の合計(uint32)
+//	ノード表:   幅優先順のノードごとに ラベル位置(uint32) | 最初の子(uint32) | 部分木キー数(uint32) | 子の数と終端フラグ(uint32)
+//	先頭バイト: ノードごとのラベルの先頭バイト（兄弟ノードは昇順に連続するため、子の探索はこの配列の二分探索）
+//	ラベル:     幅優先順に連結したラベル（ノードiのラベルは次のノードのラベル位置まで）
+//	トレーラ:   ヘッダからラベルまでのCRC-32C(uint32)
+const (
+	flatMagic   = "PTRF"
+	flatVersion = 1
+
+	flatHeaderSize = 16
+	flatNodeSize   = 16
+
+	// flatTerminal 子の数と終端フラグのフィールドで、終端ノードを表すビット
+	flatTerminal = 1 << 31
+)
+
+// flatTrie フラット形式のバイト列を解釈する読み取り専用のトライ（ノードの番号は幅優先順で根が0）
+type flatTrie struct {
+	nodes      []byte
+	firstBytes []byte
+	labels     []byte
+	nodeCount  int
+}
+
+// WriteFlat トライをOpenMappedで開けるフラット形式で書き出し、書き出したバイト数を返す（値は書き出さない）
+func (t *Trie[V]) WriteFlat(w io.Writer) (int64, error) {
+	flat, _, err := buildFlat(t.root)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &countingWriter{w: w}
+	checksum := crc32.New(castagnoli)
+	bw := bufio.NewWriter(io.MultiWriter(counter, checksum))
+
+	header := make([]byte, 0, flatHeaderSize)
+	header = append(header, flatMagic...)
+	header = binary.LittleEndian.AppendUint16(header, flatVersion)
+	header = binary.LittleEndian.AppendUint16(header, 0)
+	header = binary.LittleEndian.AppendUint32(header, uint32(flat.nodeCount))   //nolint:gosec // 上限を確認済み
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(flat.labels))) //nolint:gosec // 上限を確認済み
+
+	// bufio.Writerは最初のエラーを保持し、Flushで返す
+	_, _ = bw.Write(header)
+	_, _ = bw.Write(flat.nodes)
+	_, _ = bw.Write(flat.firstBytes)
+	_, _ = bw.Write(flat.labels)
+
+	err = bw.Flush()
+	if err != nil {
+		return counter.n, fmt.Errorf("write flat trie: %w", err)
+	}
+
+	_, err = counter.Write(binary.LittleEndian.AppendUint32(nil, checksum.Sum32()))
+	if err != nil {
+		return counter.n, fmt.Errorf("write checksum: %w", err)
+	}
+
+	return counter.n, nil
+}
+
+// buildFlat 根ノード以下をフラット形式の配列に変換し、番号順のノードも返す
+func buildFlat[V any](root *Node[V]) (*flatTrie, []*Node[V], error) {
+	// 幅優先順に並べると、各ノードの子ノードの番号が連続する
+	order := []*Node[V]{root}
+	labelBytes := 0
+
+	for i := 0; i < len(order); i++ {
+		node := order[i]
+		labelBytes += len(node.label)
+
+		for j := range node.ChildrenCount() {
+			order = append(order, node.childAt(j))
+		}
+	}
+
+	if uint64(labelBytes) > math.MaxUint32 || uint64(len(order)) > math.MaxUint32 {
+		return nil, nil, fmt.Errorf("%w: %d nodes and %d label bytes exceed the flat format", ErrInvalidFormat, len(order), labelBytes)
+	}
+
+	flat := &flatTrie{
+		nodes:      make([]byte, len(order)*flatNodeSize),
+		firstBytes: make([]byte, len(order)),
+		labels:     make([]byte, 0, labelBytes),
+		nodeCount:  len(order),
+	}
+
+	nextChild := 1
+
+	for i, node := range order {
+		childField := uint32(node.ChildrenCount()) //nolint:gosec // 子ノード数は256以下
+		if node.isEndOfKey {
+			childField |= flatTerminal
+		}
+
+		record := flat.nodes[i*flatNodeSize:]
+		binary.LittleEndian.PutUint32(record[0:], uint32(len(flat.labels))) //nolint:gosec // 上限を確認済み
+		binary.LittleEndian.PutUint32(record[4:], uint32(nextChild))        //nolint:gosec // 上限を確認済み
+		binary.LittleEndian.PutUint32(record[8:], uint32(node.count))       //nolint:gosec // キー数はノード数以下
+		binary.LittleEndian.PutUint32(record[12:], childField)
+
+		// 根のラベルは空のため先頭バイトは0のまま
+		if node.label != "" {
+			flat.firstBytes[i] = node.label[0]
+		}
+
+		flat.labels = append(flat.labels, node.label...)
+		nextChild += node.ChildrenCount()
+	}
+
+	return flat, order, nil
+}
+
+// parseFlat フラット形式のバイト列を検証して解釈する（dataはコピーせずに参照する）
+func parseFlat(data []byte) (*flatTrie, error) {
+	if len(data) < flatHeaderSize+4 {
+		return nil, fmt.Errorf("%w: %d bytes is too short", ErrInvalidFormat, len(data))
+	}
+
+	if string(data[:len(flatMagic)]) != flatMagic {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrInvalidFormat, data[:len(flatMagic)])
+	}
+
+	version := binary.LittleEndian.Uint16(data[4:])
+	if version != flatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	nodeCount := binary.LittleEndian.Uint32(data[8:])
+	labelBytes := binary.LittleEndian.Uint32(data[12:])
+
+	// 32ビット環境でもあふれないよう、期待するサイズはuint64で計算してから比較
+	size := flatHeaderSize + uint64(nodeCount)*(flatNodeSize+1) + uint64(labelBytes) + 4
+	if nodeCount == 0 || uint64(len(data)) != size {
+		return nil, fmt.Errorf("%w: %d bytes, expected %d for %d nodes", ErrInvalidFormat, len(data), size, nodeCount)
+	}
+
+	body, trailer := data[:len(data)-4], data[len(data)-4:]
+	if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(trailer) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidFormat)
+	}
+
+	// サイズがlen(data)と一致したため、以降の位置はintに収まる
+	nodes := int(nodeCount)
+	nodesEnd := flatHeaderSize + nodes*flatNodeSize
+	flat := &flatTrie{
+		nodes:      body[flatHeaderSize:nodesEnd],
+		firstBytes: body[nodesEnd : nodesEnd+nodes],
+		labels:     body[nodesEnd+nodes:],
+		nodeCount:  nodes,
+	}
+
+	err := flat.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+// validate 参照の範囲と兄弟ノードの順序を確認（以降の参照で範囲外アクセスが起きないことを保証する）
+func (f *flatTrie) validate() error {
+	nextChild := 1
+
+	for i := range f.nodeCount {
+		first, count := f.children(i)
+		if first != nextChild || first+count > f.nodeCount {
+			return fmt.Errorf("%w: node %d has children [%d, %d)", ErrInvalidFormat, i, first, first+count)
+		}
+
+		nextChild += count
+
+		// 根のラベルだけが空（32ビット環境ではラベル位置が負の値に変換され得る）
+		start, end := f.labelRange(i)
+		if start < 0 || start > end || end > len(f.labels) || (i > 0) != (start < end) {
+			return fmt.Errorf("%w: node %d has label [%d, %d)", ErrInvalidFormat, i, start, end)
+		}
+
+		if i > 0 && f.labels[start] != f.firstBytes[i] {
+			return fmt.Errorf("%w: node %d first byte mismatch", ErrInvalidFormat, i)
+		}
+
+		for j := first + 1; j < first+count; j++ {
+			if f.firstBytes[j-1] >= f.firstBytes[j] {
+				return fmt.Errorf("%w: children of node %d out of order", ErrInvalidFormat, i)
+			}
+		}
+	}
+
+	// 根以外のすべてのノードがちょうど1つの親を持つ
+	if nextChild != f.nodeCount {
+		return fmt.Errorf("%w: %d nodes are reachable, header says %d", ErrInvalidFormat, nextChild, f.nodeCount)
+	}
+
+	return nil
+}
+
+// field ノードiのj番目のフィールドを取得
+func (f *flatTrie) field(i, j int) uint32 {
+	return binary.LittleEndian.Uint32(f.nodes[i*flatNodeSize+j*4:])
+}
+
+// labelRange ノードiのラベルのラベル領域での範囲
+func (f *flatTrie) labelRange(i int) (int, int) {
+	start := int(f.field(i, 0))
+	if i+1 == f.nodeCount {
+		return start, len(f.labels)
+	}
+
+	return start, int(f.field(i+1, 0))
+}
+
+// label ノードiのラベル
+func (f *flatTrie) label(i int) []byte {
+	start, end := f.labelRange(i)
+
+	return f.labels[start:end]
+}
+
+// children ノードiの最初の子ノードの番号と子ノードの数
+func (f *flatTrie) children(i int) (int, int) {
+	return int(f.field(i, 1)), int(f.field(i, 3) &^ flatTerminal)
+}
+
+// count ノードiを根とする部分木のキー数
+func (f *flatTrie) count(i int) int {
+	return int(f.field(i, 2))
+}
+
+// isTerminal ノードiがキーの終端かどうか
+func (f *flatTrie) isTerminal(i int) bool {
+	return f.field(i, 3)&flatTerminal != 0
+}
+
+// child ノードiの子ノードのうち、ラベルが先頭バイトbで始まるものの番号を取得
+func (f *flatTrie) child(i int, b byte) (int, bool) {
+	first, count := f.children(i)
+	siblings := f.firstBytes[first : first+count]
+
+	// 子の少ないノードでは線形探索の方が速い
+	if count <= node16Capacity {
+		for j, c := range siblings {
+			if c >= b {
+				return first + j, c == b
+			}
+		}
+
+		return first + count, false
+	}
+
+	j, found := slices.BinarySearch(siblings, b)
+
+	return first + j, found
+}
+
+// search キーに完全一致する終端ノードの番号を取得
+func (f *flatTrie) search(key string) (int, bool) {
+	node := 0
+
+	for len(key) > 0 {
+		child, exists := f.child(node, key[0])
+		if !exists {
+			return 0, false
+		}
+
+		label := f.label(child)
+		if len(key) < len(label) || key[:len(label)] != string(label) {
+			return 0, false
+		}
+
+		key = key[len(label):]
+		node = child
+	}
+
+	return node, f.isTerminal(node)
+}
+
+// findPrefixNode プレフィックスを持つキーがすべて含まれる部分木の根ノードの番号を取得
+// ノードまでのキーはprefix[:keyStart]にノードのラベルを続けたもの（キーの文字列は作らない）
+func (f *flatTrie) findPrefixNode(prefix string) (int, int, bool) {
+	node := 0
+	consumed := 0
+
+	for consumed < len(prefix) {
+		remaining := prefix[consumed:]
+
+		child, exists := f.child(node, remaining[0])
+		if !exists {
+			return 0, 0, false
+		}
+
+		label := f.label(child)
+
+		// プレフィックスの残りがラベル内で終わる場合
+		if len(remaining) <= len(label) {
+			if string(label[:len(remaining)]) != remaining {
+				return 0, 0, false
+			}
+
+			return child, consumed, true
+		}
+
+		if remaining[:len(label)] != string(label) {
+			return 0, 0, false
+		}
+
+		consumed += len(label)
+		node = child
+	}
+
+	return node, consumed - len(f.label(node)), true
+}
+
+// walk ノードi以下の終端ノードを指定された順序で辿り、キーとノードの番号をfnに渡す（中断された場合はfalseを返す）
+// ノードのキーはbaseにノードのラベルを続けたもの。keyは走査全体で使い回すバッファのため、fnの呼び出し中のみ有効
+// pruneがtrueを返したノードの部分木は辿らない（nilの場合はすべて辿る）
+func (f *flatTrie) walk(node int, base string, order Order, prune func(key []byte) bool, fn func(key []byte, node int) bool) bool {
+	type frame struct {
+		node, next, keyLen int
+	}
+
+	key := append(make([]byte, 0, len(base)+64), base...)
+	key = append(key, f.label(node)...)
+
+	if prune != nil && prune(key) {
+		return true
+	}
+
+	stack := []frame{{node: node, keyLen: len(key)}}
+
+	// 昇順では親キーが子孫より先、降順では後に来る
+	if !f.visitIn(order, Ascending, key, node, fn) {
+		return false
+	}
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+
+		first, count := f.children(top.node)
+		if top.next == count {
+			stack = stack[:len(stack)-1]
+
+			if !f.visitIn(order, Descending, key[:top.keyLen], top.node, fn) {
+				return false
+			}
+
+			continue
+		}
+
+		// 降順では子ノードを逆から辿る
+		child := first + top.next
+		if order == Descending {
+			child = first + count - 1 - top.next
+		}
+
+		top.next++
+
+		key = append(key[:top.keyLen], f.label(child)...)
+		if prune != nil && prune(key) {
+			continue
+		}
+
+		stack = append(stack, frame{node: child, keyLen: len(key)})
+
+		if !f.visitIn(order, Ascending, key, child, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// visitIn 走査の順序がwantで、ノードが終端の場合にfnを呼び出す（fnがfalseを返した場合のみfalseを返す）
+func (f *flatTrie) visitIn(order, want Order, key []byte, node int, fn func(key []byte, node int) bool) bool {
+	return order != want || !f.isTerminal(node) || fn(key, node)
+}
+
+// visitPrefixesOf sのプレフィックスとなるキーの終端ノードを短い順に訪問
+// fnにはキーの長さと終端ノードの番号が渡され、falseを返すと訪問を中断
+func (f *flatTrie) visitPrefixesOf(s string, fn func(n, node int) bool) {
+	node := 0
+	consumed := 0
+
+	for {
+		if f.isTerminal(node) && !fn(consumed, node) {
+			return
+		}
+
+		if consumed == len(s) {
+			return
+		}
+
+		child, exists := f.child(node, s[consumed])
+		if !exists {
+			return
+		}
+
+		// ラベル全体がsの残りと一致しない場合、これより長いプレフィックスは存在しない
+		label := f.label(child)
+		if len(s)-consumed < len(label) || s[consumed:consumed+len(label)] != string(label) {
+			return
+		}
+
+		consumed += len(label)
+		node = child
+	}
+}
+
+// selectKey バイト順の昇順でi番目（0始まり）のキーを取得
+func (f *flatTrie) selectKey(i int) (string, bool) {
+	if i < 0 || i >= f.count(0) {
+		return "", false
+	}
+
+	var key []byte
+
+	node := 0
+
+	for {
+		// 自身のキーは子孫のキーより前に並ぶ
+		if f.isTerminal(node) {
+			if i == 0 {
+				return string(key), true
+			}
+
+			i--
+		}
+
+		// 部分木のキー数を使ってi番目のキーを含む子ノードを選ぶ
+		child, count := f.children(node)
+		for end := child + count; child < end-1 && i >= f.count(child); child++ {
+			i -= f.count(child)
+		}
+
+		key = append(key, f.label(child)...)
+		node = child
+	}
+}
+
+// rank バイト順でkeyより前に並ぶキーの数を取得
+func (f *flatTrie) rank(key string) int {
+	node := 0
+	consumed := 0
+	rank := 0
+
+	for consumed < len(key) {
+		remaining := key[consumed:]
+
+		// 自身のキーはkeyの真のプレフィックスなのでkeyより前
+		if f.isTerminal(node) {
+			rank++
+		}
+
+		first, count := f.children(node)
+		next := -1
+
+		for child := first; child < first+count; child++ {
+			label := f.label(child)
+			if label[0] < remaining[0] {
+				rank += f.count(child)
+
+				continue
+			}
+
+			if label[0] > remaining[0] {
+				break
+			}
+
+			// ラベル全体が一致すれば降下し、ラベルの途中でkeyより小さくなれば部分木全体が前に並ぶ
+			commonLen := commonPrefixLength(string(label), remaining)
+
+			switch {
+			case commonLen == len(label):
+				next = child
+			case commonLen < len(remaining) && label[commonLen] < remaining[commonLen]:
+				rank += f.count(child)
+			}
+
+			break
+		}
+
+		if next < 0 {
+			return rank
+		}
+
+		consumed += len(f.label(next))
+		node = next
+	}
+
+	return rank
+}
@@ -0,0 +1,238 @@
+package patriciatrie
+
+import (
+	"iter"
+	"reflect"
+)
+
+// FrozenTrie 構築済みのトライを連続した配列に変換した読み取り専用のトライ
+// ノードはポインタを持たず、幅優先順のノード表・先頭バイト表・ラベル領域（WriteFlatと同じ配置）で表す
+type FrozenTrie[V any] struct {
+	flat *flatTrie
+
+	// ノードの番号で引く値（値がすべてゼロ値の場合はnil）
+	values []V
+}
+
+// Freeze トライの現在の内容から読み取り専用のFrozenTrieを作成（以降のトライの変更は反映されない）
+// ノードの番号とラベルの位置はuint32で表すため、ラベルの合計が4GiBを超えるトライは変換できずエラーを返す
+func (t *Trie[V]) Freeze() (*FrozenTrie[V], error) {
+	flat, order, err := buildFlat(t.root)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen := &FrozenTrie[V]{flat: flat}
+
+	// キー集合として使うトライでは値の配列を持たない
+	for _, node := range order {
+		if node.isEndOfKey && !reflect.ValueOf(&node.value).Elem().IsZero() {
+			frozen.values = make([]V, len(order))
+
+			break
+		}
+	}
+
+	if frozen.values != nil {
+		for i, node := range order {
+			frozen.values[i] = node.value
+		}
+	}
+
+	return frozen, nil
+}
+
+// value ノードiの値を取得
+func (f *FrozenTrie[V]) value(i int) V {
+	if f.values == nil {
+		var zero V
+
+		return zero
+	}
+
+	return f.values[i]
+}
+
+// Len 格納されているキーの数を取得
+func (f *FrozenTrie[V]) Len() int {
+	return f.flat.count(0)
+}
+
+// Search キーが存在するかを検索
+func (f *FrozenTrie[V]) Search(key string) bool {
+	_, found := f.flat.search(key)
+
+	return found
+}
+
+// Get キーに対応する値を取得
+func (f *FrozenTrie[V]) Get(key string) (V, bool) {
+	node, found := f.flat.search(key)
+	if !found {
+		var zero V
+
+		return zero, false
+	}
+
+	return f.value(node), true
+}
+
+// CountPrefix 指定されたプレフィックスを持つキーの数を取得
+func (f *FrozenTrie[V]) CountPrefix(prefix string) int {
+	node, _, found := f.flat.findPrefixNode(prefix)
+	if !found {
+		return 0
+	}
+
+	return f.flat.count(node)
+}
+
+// FindByPrefix 指定されたプレフィックスを持つすべてのキーをバイト順の昇順で検索
+func (f *FrozenTrie[V]) FindByPrefix(prefix string) []string {
+	return f.FindByPrefixOrder(prefix, Ascending)
+}
+
+// FindByPrefixOrder 指定されたプレフィックスを持つすべてのキーを指定された順序で検索
+func (f *FrozenTrie[V]) FindByPrefixOrder(prefix string, order Order) []string {
+	var result []string
+
+	f.walkPrefix(prefix, order, func(key string, _ V) bool {
+		result = append(result, key)
+
+		return true
+	})
+
+	return result
+}
+
+// Walk すべてのキーと値を指定された順序で走査
+func (f *FrozenTrie[V]) Walk(order Order, fn WalkFunc[V]) {
+	f.walkPrefix("", order, fn)
+}
+
+// All すべてのキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (f *FrozenTrie[V]) All() iter.Seq2[string, V] {
+	return f.WithPrefix("")
+}
+
+// Keys すべてのキーをバイト順の昇順で遅延列挙するイテレータを取得
+func (f *FrozenTrie[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		f.walkPrefix("", Ascending, func(key string, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// WithPrefix 指定されたプレフィックスを持つキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (f *FrozenTrie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		f.walkPrefix(prefix, Ascending, yield)
+	}
+}
+
+// walkPrefix 指定されたプレフィックスを持つキーと値を指定された順序で走査
+func (f *FrozenTrie[V]) walkPrefix(prefix string, order Order, fn WalkFunc[V]) {
+	node, keyStart, found := f.flat.findPrefixNode(prefix)
+	if !found {
+		return
+	}
+
+	f.flat.walk(node, prefix[:keyStart], order, nil, func(key []byte, node int) bool {
+		return fn(string(key), f.value(node))
+	})
+}
+
+// LongestPrefixOf sのプレフィックスとなる格納済みキーのうち最長のものと、その値を取得
+func (f *FrozenTrie[V]) LongestPrefixOf(s string) (string, V, bool) {
+	var (
+		value  V
+		length int
+		found  bool
+	)
+
+	f.flat.visitPrefixesOf(s, func(n, node int) bool {
+		length, value, found = n, f.value(node), true
+
+		return true
+	})
+
+	return s[:length], value, found
+}
+
+// ShortestPrefixOf sのプレフィックスとなる格納済みキーのうち最短のものと、その値を取得
+func (f *FrozenTrie[V]) ShortestPrefixOf(s string) (string, V, bool) {
+	var (
+		value  V
+		length int
+		found  bool
+	)
+
+	f.flat.visitPrefixesOf(s, func(n, node int) bool {
+		length, value, found = n, f.value(node), true
+
+		return false
+	})
+
+	return s[:length], value, found
+}
+
+// PrefixesOf sのプレフィックスとなる格納済みキーをすべて短い順に取得（共通プレフィックス検索）
+func (f *FrozenTrie[V]) PrefixesOf(s string) []PrefixMatch[V] {
+	var result []PrefixMatch[V]
+
+	f.flat.visitPrefixesOf(s, func(n, node int) bool {
+		result = append(result, PrefixMatch[V]{Key: s[:n], Length: n, Value: f.value(node)})
+
+		return true
+	})
+
+	return result
+}
+
+// PrefixesOfSeq sのプレフィックスとなる格納済みキーと値を短い順に遅延列挙するイテレータを取得
+func (f *FrozenTrie[V]) PrefixesOfSeq(s string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		f.flat.visitPrefixesOf(s, func(n, node int) bool {
+			return yield(s[:n], f.value(node))
+		})
+	}
+}
+
+// Select バイト順の昇順でi番目（0始まり）のキーを取得
+func (f *FrozenTrie[V]) Select(i int) (string, bool) {
+	return f.flat.selectKey(i)
+}
+
+// Rank バイト順でkeyより前に並ぶキーの数を取得（keyが存在する場合はその順位と一致）
+func (f *FrozenTrie[V]) Rank(key string) int {
+	return f.flat.rank(key)
+}
+
+// Range 半開区間[from, to)に含まれるキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (f *FrozenTrie[V]) Range(from, to string) iter.Seq2[string, V] {
+	return f.RangeBounds(from, Inclusive, to, Exclusive)
+}
+
+// RangeBounds fromからtoまでのキーと値を、端点の扱いを指定してバイト順の昇順で遅延列挙するイテレータを取得
+func (f *FrozenTrie[V]) RangeBounds(from string, fromBound Bound, to string, toBound Bound) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		// キーがfromのプレフィックスでなくfromより前なら、部分木のキーはすべてfromより前
+		below := func(key []byte) bool {
+			return string(key) < from && (len(key) > len(from) || string(key) != from[:len(key)])
+		}
+
+		f.flat.walk(0, "", Ascending, below, func(key []byte, node int) bool {
+			if string(key) < from || (fromBound == Exclusive && string(key) == from) {
+				return true
+			}
+
+			// 上端を超えたら終了
+			if string(key) > to || (toBound == Exclusive && string(key) == to) {
+				return false
+			}
+
+			return yield(string(key), f.value(node))
+		})
+	}
+}
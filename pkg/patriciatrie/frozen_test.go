@@ -0,0 +1,195 @@
+package patriciatrie
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyValue 列挙されたキーと値の組
+type keyValue struct {
+	key   string
+	value int
+}
+
+// collectPairs イテレータが列挙するキーと値の組を順に集める
+func collectPairs(seq iter.Seq2[string, int]) []keyValue {
+	var result []keyValue
+
+	for key, value := range seq {
+		result = append(result, keyValue{key, value})
+	}
+
+	return result
+}
+
+func TestTrie_Freeze(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "", "cat", "cats", "car", "dog", "do", "東京", "東京都")
+
+	frozen, err := trie.Freeze()
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, frozen.Len())
+	assert.True(t, frozen.Search("東京都"))
+	assert.False(t, frozen.Search("ca"))
+
+	value, found := frozen.Get("cats")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	value, found = frozen.Get("")
+	assert.True(t, found)
+	assert.Equal(t, 0, value)
+
+	_, found = frozen.Get("catsup")
+	assert.False(t, found)
+
+	assert.Equal(t, []string{"car", "cat", "cats"}, frozen.FindByPrefix("ca"))
+	assert.Equal(t, []string{"cats", "cat", "car"}, frozen.FindByPrefixOrder("ca", Descending))
+	assert.Equal(t, 2, frozen.CountPrefix("東"))
+
+	key, value, found := frozen.LongestPrefixOf("東京都庁")
+	assert.True(t, found)
+	assert.Equal(t, "東京都", key)
+	assert.Equal(t, 7, value)
+
+	key, _, found = frozen.ShortestPrefixOf("cats")
+	assert.True(t, found)
+	assert.Empty(t, key)
+
+	assert.Equal(t, []PrefixMatch[int]{
+		{Key: "", Length: 0, Value: 0},
+		{Key: "cat", Length: 3, Value: 1},
+		{Key: "cats", Length: 4, Value: 2},
+	}, frozen.PrefixesOf("catsup"))
+
+	// 変換後のトライの変更は反映されない
+	_, err = trie.Put("cow", 8)
+	require.NoError(t, err)
+	_, err = trie.Delete("cat")
+	require.NoError(t, err)
+
+	assert.False(t, frozen.Search("cow"))
+	assert.True(t, frozen.Search("cat"))
+	assert.Equal(t, 8, frozen.Len())
+}
+
+func TestTrie_Freeze_MatchesTrie(t *testing.T) {
+	t.Parallel()
+
+	trie := newRandomIntTrie(t, 1, 3000, "abcde")
+
+	frozen, err := trie.Freeze()
+	require.NoError(t, err)
+
+	assert.Equal(t, trie.Len(), frozen.Len())
+	assert.Equal(t, collectPairs(trie.All()), collectPairs(frozen.All()))
+	assert.Equal(t, slices.Collect(trie.Keys()), slices.Collect(frozen.Keys()))
+
+	for _, query := range []string{"", "a", "ab", "abc", "e", "eeee", "abcdeabcde", "x"} {
+		assert.Equal(t, trie.Search(query), frozen.Search(query), query)
+		assert.Equal(t, trie.FindByPrefix(query), frozen.FindByPrefix(query), query)
+		assert.Equal(t, trie.FindByPrefixOrder(query, Descending), frozen.FindByPrefixOrder(query, Descending), query)
+		assert.Equal(t, trie.CountPrefix(query), frozen.CountPrefix(query), query)
+		assert.Equal(t, trie.PrefixesOf(query), frozen.PrefixesOf(query), query)
+		assert.Equal(t, trie.Rank(query), frozen.Rank(query), query)
+		assert.Equal(t, collectPairs(trie.WithPrefix(query)), collectPairs(frozen.WithPrefix(query)), query)
+
+		wantValue, wantFound := trie.Get(query)
+		gotValue, gotFound := frozen.Get(query)
+		assert.Equal(t, wantFound, gotFound, query)
+		assert.Equal(t, wantValue, gotValue, query)
+
+		wantKey, wantValue, wantFound := trie.LongestPrefixOf(query)
+		gotKey, gotValue, gotFound := frozen.LongestPrefixOf(query)
+		assert.Equal(t, wantFound, gotFound, query)
+		assert.Equal(t, wantKey, gotKey, query)
+		assert.Equal(t, wantValue, gotValue, query)
+
+		wantKey, wantValue, wantFound = trie.ShortestPrefixOf(query)
+		gotKey, gotValue, gotFound = frozen.ShortestPrefixOf(query)
+		assert.Equal(t, wantFound, gotFound, query)
+		assert.Equal(t, wantKey, gotKey, query)
+		assert.Equal(t, wantValue, gotValue, query)
+	}
+
+	for _, r := range []struct{ from, to string }{{"", "b"}, {"abc", "abd"}, {"b", "b"}, {"cc", "eeeeeeeee"}, {"d", "a"}} {
+		assert.Equal(t, collectPairs(trie.Range(r.from, r.to)), collectPairs(frozen.Range(r.from, r.to)), r)
+		assert.Equal(t,
+			collectPairs(trie.RangeBounds(r.from, Exclusive, r.to, Inclusive)),
+			collectPairs(frozen.RangeBounds(r.from, Exclusive, r.to, Inclusive)), r)
+	}
+
+	for _, i := range []int{-1, 0, 1, trie.Len() / 2, trie.Len() - 1, trie.Len()} {
+		wantKey, wantFound := trie.Select(i)
+		gotKey, gotFound := frozen.Select(i)
+		assert.Equal(t, wantFound, gotFound, i)
+		assert.Equal(t, wantKey, gotKey, i)
+	}
+}
+
+func TestTrie_Freeze_KeySet(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"apple", "app", "banana"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	frozen, err := trie.Freeze()
+	require.NoError(t, err)
+
+	// 値がすべてゼロ値のトライは値の配列を持たない
+	assert.Nil(t, frozen.values)
+
+	value, found := frozen.Get("app")
+	assert.True(t, found)
+	assert.Nil(t, value)
+	assert.Equal(t, []string{"app", "apple", "banana"}, slices.Collect(frozen.Keys()))
+}
+
+func TestTrie_Freeze_Empty(t *testing.T) {
+	t.Parallel()
+
+	frozen, err := NewTrie[int]().Freeze()
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, frozen.Len())
+	assert.False(t, frozen.Search(""))
+	assert.Empty(t, frozen.FindByPrefix(""))
+	assert.Empty(t, frozen.PrefixesOf("abc"))
+	assert.Zero(t, frozen.Rank("a"))
+
+	_, found := frozen.Select(0)
+	assert.False(t, found)
+
+	_, _, found = frozen.LongestPrefixOf("a")
+	assert.False(t, found)
+}
+
+//nolint:paralleltest // AllocsPerRun must not run in parallel
+func TestFrozenTrie_SearchDoesNotAllocate(t *testing.T) {
+	trie := NewTrie[int]()
+	for i, key := range []string{"cat", "cats", "car", "dog"} {
+		_, err := trie.Put(key, i+1)
+		require.NoError(t, err)
+	}
+
+	frozen, err := trie.Freeze()
+	require.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = frozen.Search("cats")
+		_, _ = frozen.Get("car")
+		_ = frozen.CountPrefix("ca")
+		_, _, _ = frozen.LongestPrefixOf("category")
+		_ = frozen.Rank("cow")
+	})
+	assert.Zero(t, allocs)
+}
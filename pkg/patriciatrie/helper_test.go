@@ -0,0 +1,42 @@
+package patriciatrie
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newIntTrie キーを順に追加し、値にキーの添字を持つテスト用のトライを作成
+func newIntTrie(t *testing.T, keys ...string) *Trie[int] {
+	t.Helper()
+
+	trie := NewTrie[int]()
+	for i, key := range keys {
+		_, err := trie.Put(key, i)
+		require.NoError(t, err)
+	}
+
+	return trie
+}
+
+// newRandomIntTrie alphabetの文字からなる長さ0から8のキーを乱数で生成してn回追加したテスト用のトライを作成
+// 値は追加した順の添字（同じキーが生成された場合は後の添字で上書き）で、同じseedからは同じトライができる
+func newRandomIntTrie(t *testing.T, seed int64, n int, alphabet string) *Trie[int] {
+	t.Helper()
+
+	rng := rand.New(rand.NewSource(seed))
+
+	trie := NewTrie[int]()
+	for i := range n {
+		key := make([]byte, rng.Intn(9))
+		for j := range key {
+			key[j] = alphabet[rng.Intn(len(alphabet))]
+		}
+
+		_, err := trie.Put(string(key), i)
+		require.NoError(t, err)
+	}
+
+	return trie
+}
@@ -0,0 +1,31 @@
+package patriciatrie
+
+import "iter"
+
+// All すべてのキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.walkNode(t.root, t.root.label, Ascending, yield)
+	}
+}
+
+// Keys すべてのキーをバイト順の昇順で遅延列挙するイテレータを取得
+func (t *Trie[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		t.walkNode(t.root, t.root.label, Ascending, func(key string, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// WithPrefix 指定されたプレフィックスを持つキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		node, nodeKey, _ := t.findPrefixNode(prefix)
+		if node == nil {
+			return
+		}
+
+		t.walkNode(node, nodeKey, Ascending, yield)
+	}
+}
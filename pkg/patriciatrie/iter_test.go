@@ -0,0 +1,78 @@
+package patriciatrie
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrie_All(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "dog", "cat", "cats", "catalog", "dogs", "elephant")
+
+	var keys []string
+
+	for key, value := range trie.All() {
+		expected, found := trie.Get(key)
+		assert.True(t, found)
+		assert.Equal(t, expected, value)
+
+		keys = append(keys, key)
+	}
+
+	assert.Equal(t, []string{"cat", "catalog", "cats", "dog", "dogs", "elephant"}, keys)
+}
+
+func TestTrie_Keys(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "dog", "cat", "cats", "catalog", "dogs", "elephant")
+
+	assert.Equal(t, []string{"cat", "catalog", "cats", "dog", "dogs", "elephant"}, slices.Collect(trie.Keys()))
+	assert.Empty(t, slices.Collect(New().Keys()))
+}
+
+func TestTrie_WithPrefix(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "dog", "cat", "cats", "catalog", "dogs", "elephant")
+
+	tests := []struct {
+		name     string
+		prefix   string
+		expected map[string]int
+	}{
+		{"プレフィックス: cat", "cat", map[string]int{"cat": 1, "cats": 2, "catalog": 3}},
+		{"プレフィックス: do", "do", map[string]int{"dog": 0, "dogs": 4}},
+		{"ラベル途中で終わるプレフィックス", "ele", map[string]int{"elephant": 5}},
+		{"マッチしないプレフィックス", "xyz", map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, maps.Collect(trie.WithPrefix(tt.prefix)))
+		})
+	}
+}
+
+func TestTrie_IteratorBreak(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "dog", "cat", "cats", "catalog", "dogs", "elephant")
+
+	var keys []string
+
+	for key := range trie.WithPrefix("cat") {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"cat", "catalog"}, keys)
+}
@@ -1,9 +1,14 @@
 package patriciatrie
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -21,13 +26,83 @@ func BenchmarkTrie_Large_Insert(b *testing.B) {
 			for range b.N {
 				trie := New()
 				for _, key := range keys {
-					_ = trie.Insert(key)
+					_, _ = trie.Insert(key)
 				}
 			}
 		})
 	}
 }
 
+// BenchmarkTrie_Large_BuildSorted ソート済みキーからの構築性能（1件ずつのInsertと一括構築の比較）
+func BenchmarkTrie_Large_BuildSorted(b *testing.B) {
+	dataSizes := []int{10000, 100000, 1000000}
+
+	for _, size := range dataSizes {
+		keys := slices.Compact(slices.Sorted(slices.Values(generateLargeRandomKeys(size, 20))))
+
+		b.Run(fmt.Sprintf("Insert/Keys_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for range b.N {
+				trie := New()
+				for _, key := range keys {
+					_, _ = trie.Insert(key)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("FromSorted/Keys_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for range b.N {
+				_, _ = FromSorted[any](keys)
+			}
+		})
+	}
+}
+
+// BenchmarkTrie_Large_ReadFrom 保存したトライの読み込み性能（ソート済みキーからの構築との比較）
+func BenchmarkTrie_Large_ReadFrom(b *testing.B) {
+	dataSizes := []int{100000, 1000000}
+
+	for _, size := range dataSizes {
+		keys := slices.Compact(slices.Sorted(slices.Values(generateLargeRandomKeys(size, 20))))
+
+		trie, err := FromSorted[any](keys)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var saved bytes.Buffer
+
+		_, err = trie.WriteTo(&saved)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("FromSorted/Keys_%d", size), func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for range b.N {
+				_, _ = FromSorted[any](keys)
+			}
+		})
+
+		b.Run(fmt.Sprintf("ReadFrom/Keys_%d", size), func(b *testing.B) {
+			b.SetBytes(int64(saved.Len()))
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for range b.N {
+				_, _ = New().ReadFrom(bytes.NewReader(saved.Bytes()))
+			}
+		})
+	}
+}
+
 // BenchmarkTrie_Large_Search 大規模データでの検索性能測定
 func BenchmarkTrie_Large_Search(b *testing.B) {
 	dataSizes := []int{10000, 100000, 1000000}
@@ -39,7 +114,7 @@ func BenchmarkTrie_Large_Search(b *testing.B) {
 
 			// 事前データ挿入
 			for _, key := range keys {
-				_ = trie.Insert(key)
+				_, _ = trie.Insert(key)
 			}
 
 			b.ResetTimer()
@@ -72,11 +147,12 @@ func BenchmarkTrie_Large_Memory(b *testing.B) {
 
 				trie := New()
 				for _, key := range keys {
-					_ = trie.Insert(key)
+					_, _ = trie.Insert(key)
 				}
 
 				runtime.GC()
 				runtime.ReadMemStats(&m2)
+				runtime.KeepAlive(trie)
 
 				// メモリ使用量を記録（非公式だが参考値として）
 				memUsed := m2.Alloc - m1.Alloc
@@ -97,7 +173,7 @@ func BenchmarkTrie_Large_Mixed(b *testing.B) {
 
 			// 初期データの半分を挿入
 			for i := range len(keys) / 2 {
-				_ = trie.Insert(keys[i])
+				_, _ = trie.Insert(keys[i])
 			}
 
 			b.ResetTimer()
@@ -108,7 +184,7 @@ func BenchmarkTrie_Large_Mixed(b *testing.B) {
 
 				switch i % 4 {
 				case 0: // 25% 挿入
-					_ = trie.Insert(key)
+					_, _ = trie.Insert(key)
 				case 1, 2: // 50% 検索
 					_ = trie.Search(key)
 				case 3: // 25% プレフィックス検索
@@ -132,7 +208,7 @@ func BenchmarkTrie_Large_PrefixSearch(b *testing.B) {
 
 			// データ挿入
 			for _, key := range keys {
-				_ = trie.Insert(key)
+				_, _ = trie.Insert(key)
 			}
 
 			// プレフィックス候補を準備
@@ -158,7 +234,444 @@ func BenchmarkTrie_Large_PrefixSearch(b *testing.B) {
 	}
 }
 
+// BenchmarkTrie_Large_PrefixScaling プレフィックス検索のコストが結果件数に比例することの確認
+// トライ全体のサイズを変えても、同じ長さのプレフィックスに対する1件あたりのコストはほぼ一定になる
+func BenchmarkTrie_Large_PrefixScaling(b *testing.B) {
+	dataSizes := []int{10000, 100000, 1000000}
+	prefixLengths := []int{1, 2, 3}
+
+	for _, size := range dataSizes {
+		b.Run(fmt.Sprintf("Keys_%d", size), func(b *testing.B) {
+			keys := generateLargeRandomKeys(size, 15)
+			trie := New()
+
+			for _, key := range keys {
+				_, _ = trie.Insert(key)
+			}
+
+			for _, prefixLen := range prefixLengths {
+				b.Run(fmt.Sprintf("PrefixLen_%d", prefixLen), func(b *testing.B) {
+					prefixes := make([]string, 1000)
+					for i := range prefixes {
+						key := keys[rand.Intn(len(keys))]
+						prefixes[i] = key[:minValue(len(key), prefixLen)]
+					}
+
+					totalResults := 0
+
+					b.ResetTimer()
+					b.ReportAllocs()
+
+					for i := range b.N {
+						results := trie.FindByPrefix(prefixes[i%len(prefixes)])
+						totalResults += len(results)
+					}
+
+					b.StopTimer()
+
+					// 1クエリあたりの結果件数と、結果1件あたりの時間を記録
+					b.ReportMetric(float64(totalResults)/float64(b.N), "results/op")
+
+					if totalResults > 0 {
+						b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(totalResults), "ns/result")
+					}
+				})
+			}
+		})
+	}
+}
+
+// BenchmarkTrie_Large_PrefixSelective 結果が少ないプレフィックス検索がトライ全体のサイズに依存しないことの確認
+func BenchmarkTrie_Large_PrefixSelective(b *testing.B) {
+	dataSizes := []int{10000, 100000, 1000000}
+
+	for _, size := range dataSizes {
+		b.Run(fmt.Sprintf("Keys_%d", size), func(b *testing.B) {
+			keys := generateLargeRandomKeys(size, 15)
+			trie := New()
+
+			for _, key := range keys {
+				_, _ = trie.Insert(key)
+			}
+
+			// 既存キーそのものをプレフィックスとして使用（結果はほぼ1件）
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for i := range b.N {
+				results := trie.FindByPrefix(keys[i%len(keys)])
+				_ = results
+			}
+		})
+	}
+}
+
+// BenchmarkTrie_Large_KeySetLookup キー集合の分布ごとの検索性能（ノードの分岐数の違いによる影響の測定）
+func BenchmarkTrie_Large_KeySetLookup(b *testing.B) {
+	const size = 1000000
+
+	keySets := []struct {
+		name string
+		keys func() []string
+	}{
+		{"Random", func() []string { return generateLargeRandomKeys(size, 20) }},
+		{"Sequential", func() []string { return generateSequentialKeys(size) }},
+	}
+
+	for _, keySet := range keySets {
+		b.Run(keySet.name, func(b *testing.B) {
+			keys := keySet.keys()
+			trie := New()
+
+			for _, key := range keys {
+				_, _ = trie.Insert(key)
+			}
+
+			// 挿入順と異なる順序で検索する
+			order := rand.Perm(len(keys))
+
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for i := range b.N {
+				_ = trie.Search(keys[order[i%len(order)]])
+			}
+		})
+	}
+}
+
+// BenchmarkTrie_Large_Mapped メモリマップした読み取り専用トライとポインタ構造のトライの検索性能の比較
+func BenchmarkTrie_Large_Mapped(b *testing.B) {
+	const size = 1000000
+
+	keys := generateLargeRandomKeys(size, 20)
+
+	trie := New()
+	for _, key := range keys {
+		_, _ = trie.Insert(key)
+	}
+
+	path := filepath.Join(b.TempDir(), "large.flat")
+
+	file, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = trie.WriteFlat(file)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Open", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			mapped, err := OpenMapped(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			_ = mapped.Close()
+		}
+	})
+
+	mapped, err := OpenMapped(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer func() {
+		_ = mapped.Close()
+	}()
+
+	order := rand.Perm(len(keys))
+
+	b.Run("Search/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.Search(keys[order[i%len(order)]])
+		}
+	})
+
+	b.Run("Search/Mapped", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = mapped.Search(keys[order[i%len(order)]])
+		}
+	})
+
+	b.Run("FindByPrefix/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.FindByPrefix(keys[order[i%len(order)]][:2])
+		}
+	})
+
+	b.Run("FindByPrefix/Mapped", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = mapped.FindByPrefix(keys[order[i%len(order)]][:2])
+		}
+	})
+}
+
 // BenchmarkTrie_Large_WorstCase 最悪ケースシナリオの性能測定
+// measureHeap buildを実行し、GC後に残ったヒープの増分を要素あたりのバイト数として単位unitで記録
+func measureHeap[T any](b *testing.B, count int, unit string, build func() T) {
+	b.Helper()
+
+	var m1, m2 runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&m1)
+
+	result := build()
+
+	runtime.GC()
+	runtime.ReadMemStats(&m2)
+	runtime.KeepAlive(result)
+
+	b.ReportMetric(float64(m2.Alloc-m1.Alloc)/float64(count), unit)
+}
+
+func BenchmarkTrie_Large_FrozenMemory(b *testing.B) {
+	dataSizes := []int{100000, 1000000}
+
+	for _, size := range dataSizes {
+		keys := generateLargeRandomKeys(size, 20)
+
+		b.Run(fmt.Sprintf("Trie/Keys_%d", size), func(b *testing.B) {
+			for range b.N {
+				measureHeap(b, size, "bytes/key", func() *Trie[any] {
+					trie := New()
+					for _, key := range keys {
+						_, _ = trie.Insert(key)
+					}
+
+					return trie
+				})
+			}
+		})
+
+		b.Run(fmt.Sprintf("Frozen/Keys_%d", size), func(b *testing.B) {
+			trie := New()
+			for _, key := range keys {
+				_, _ = trie.Insert(key)
+			}
+
+			for range b.N {
+				// 変換元のトライは計測前から存在するため、増分はFrozenTrieのみ
+				measureHeap(b, size, "bytes/key", func() *FrozenTrie[any] {
+					frozen, _ := trie.Freeze()
+
+					return frozen
+				})
+			}
+		})
+
+		b.Run(fmt.Sprintf("FrozenWithValues/Keys_%d", size), func(b *testing.B) {
+			trie := NewTrie[int]()
+			for i, key := range keys {
+				_, _ = trie.Put(key, i+1)
+			}
+
+			for range b.N {
+				measureHeap(b, size, "bytes/key", func() *FrozenTrie[int] {
+					frozen, _ := trie.Freeze()
+
+					return frozen
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkTrie_Large_Frozen(b *testing.B) {
+	const size = 1000000
+
+	keys := generateLargeRandomKeys(size, 20)
+
+	trie := New()
+	for _, key := range keys {
+		_, _ = trie.Insert(key)
+	}
+
+	b.Run("Freeze", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = trie.Freeze()
+		}
+	})
+
+	frozen, _ := trie.Freeze()
+	order := rand.Perm(len(keys))
+
+	b.Run("Search/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.Search(keys[order[i%len(order)]])
+		}
+	})
+
+	b.Run("Search/Frozen", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = frozen.Search(keys[order[i%len(order)]])
+		}
+	})
+
+	b.Run("FindByPrefix/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.FindByPrefix(keys[order[i%len(order)]][:2])
+		}
+	})
+
+	b.Run("FindByPrefix/Frozen", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = frozen.FindByPrefix(keys[order[i%len(order)]][:2])
+		}
+	})
+
+	b.Run("Walk/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			trie.Walk(Ascending, func(string, any) bool { return true })
+		}
+	})
+
+	b.Run("Walk/Frozen", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			frozen.Walk(Ascending, func(string, any) bool { return true })
+		}
+	})
+}
+
+func BenchmarkTrie_Large_SuccinctMemory(b *testing.B) {
+	const size = 1000000
+
+	keys := generateLargeRandomKeys(size, 20)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	b.Run("Trie", func(b *testing.B) {
+		for range b.N {
+			measureHeap(b, len(keys), "bytes/key", func() *Trie[any] {
+				trie, _ := FromSorted[any](keys)
+
+				return trie
+			})
+		}
+	})
+
+	b.Run("DoubleArray", func(b *testing.B) {
+		for range b.N {
+			measureHeap(b, len(keys), "bytes/key", func() *DoubleArray {
+				da, _ := DoubleArrayFromSorted(keys)
+
+				return da
+			})
+		}
+	})
+
+	b.Run("LOUDS", func(b *testing.B) {
+		trie, _ := FromSorted[any](keys)
+
+		for range b.N {
+			measureHeap(b, len(keys), "bytes/key", func() *LOUDSTrie {
+				louds, _ := trie.ToLOUDS()
+
+				return louds
+			})
+		}
+	})
+}
+
+func BenchmarkTrie_Large_LOUDS(b *testing.B) {
+	const size = 1000000
+
+	keys := generateLargeRandomKeys(size, 20)
+
+	trie := New()
+	for _, key := range keys {
+		_, _ = trie.Insert(key)
+	}
+
+	b.Run("Build", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = trie.ToLOUDS()
+		}
+	})
+
+	louds, err := trie.ToLOUDS()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	order := rand.Perm(len(keys))
+
+	b.Run("Search/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.Search(keys[order[i%len(order)]])
+		}
+	})
+
+	b.Run("Search/LOUDS", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = louds.Search(keys[order[i%len(order)]])
+		}
+	})
+
+	b.Run("Key/LOUDS", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_, _ = louds.Key(order[i%len(order)] % louds.Len())
+		}
+	})
+
+	b.Run("FindByPrefix/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.FindByPrefix(keys[order[i%len(order)]][:2])
+		}
+	})
+
+	b.Run("FindByPrefix/LOUDS", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = louds.FindByPrefix(keys[order[i%len(order)]][:2])
+		}
+	})
+}
+
 func BenchmarkTrie_Large_WorstCase(b *testing.B) {
 	scenarios := []struct {
 		name string
@@ -186,7 +699,7 @@ func BenchmarkTrie_Large_WorstCase(b *testing.B) {
 			for range b.N {
 				trie := New()
 				for _, key := range scenario.keys {
-					_ = trie.Insert(key)
+					_, _ = trie.Insert(key)
 				}
 
 				// 検索も含める
@@ -199,6 +712,54 @@ func BenchmarkTrie_Large_WorstCase(b *testing.B) {
 	}
 }
 
+// BenchmarkTrie_Large_WorstCaseLookup 最悪ケースのキー集合に対する検索と列挙の性能（Searchはアロケーションなし）
+func BenchmarkTrie_Large_WorstCaseLookup(b *testing.B) {
+	scenarios := []struct {
+		name string
+		keys []string
+	}{
+		{
+			name: "Sequential",
+			keys: generateSequentialKeys(10000),
+		},
+		{
+			name: "CommonPrefix",
+			keys: generateKeysWithVeryLongCommonPrefix(strings.Repeat("verylongcommonprefix", 50), 50, 10000),
+		},
+		{
+			name: "SingleChar",
+			keys: generateSingleCharKeys(10000),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		trie := New()
+		for _, key := range scenario.keys {
+			_, _ = trie.Insert(key)
+		}
+
+		b.Run(scenario.name+"/Search", func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for i := range b.N {
+				_ = trie.Search(scenario.keys[i%len(scenario.keys)])
+			}
+		})
+
+		b.Run(scenario.name+"/Keys", func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for range b.N {
+				for key := range trie.Keys() {
+					_ = key
+				}
+			}
+		})
+	}
+}
+
 // generateLargeRandomKeys 大規模ランダムキー生成
 func generateLargeRandomKeys(count, maxLength int) []string {
 	// Go 1.20以降では自動的にシードされるためSeedは不要
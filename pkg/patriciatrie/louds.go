@@ -0,0 +1,239 @@
+package patriciatrie
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// ErrLOUDSTooLarge LOUDSのビット列の長さがrank/select索引で扱える範囲を超える
+var ErrLOUDSTooLarge = errors.New("patriciatrie: LOUDS trie exceeds 2^32 bits")
+
+// LOUDSTrie LOUDS（level-order unary degree sequence）で木構造を表す簡潔なトライ（読み取り専用）
+// キーを1バイトずつの辺に展開し、幅優先順のノードごとに「子の数だけの1と区切りの0」を並べたビット列、
+// 辺のラベル（1バイト）、終端フラグ（1ビット）のみを保持するため、ノードあたり約12ビットで表せる
+//
+// キーIDは0からLen()-1の連番で、幅優先順（キーの長さ順、同じ長さではバイト順）に振られる
+type LOUDSTrie struct {
+	// 先頭に仮想的な根の親の"10"を置いたLOUDSビット列（ノードjは(j)番目の1に対応）
+	louds bitVector
+
+	// ノードjが終端かどうか（キーIDは終端ノードの中での順位）
+	terminal bitVector
+
+	// ノードjへの辺のラベル（根は0）
+	labels []byte
+}
+
+// loudsItem 構築中の幅優先探索のキューの要素
+// パトリシアトライのノードのラベルをposバイト目まで辿った位置を、1バイトの辺で表した仮想的なノードとして扱う
+type loudsItem[V any] struct {
+	node *Node[V]
+	pos  int
+}
+
+// ToLOUDS トライの現在の内容からLOUDSで符号化した簡潔なトライを作成（以降のトライの変更は反映されない）
+func (t *Trie[V]) ToLOUDS() (*LOUDSTrie, error) {
+	l := &LOUDSTrie{}
+
+	// 仮想的な根の親
+	l.louds.push(true)
+	l.louds.push(false)
+
+	queue := []loudsItem[V]{{node: t.root}}
+	l.labels = append(l.labels, 0)
+
+	for head := 0; head < len(queue); head++ {
+		item := queue[head]
+		node := item.node
+
+		if item.pos < len(node.label) {
+			// ラベルの途中は子が1つの非終端ノード
+			l.louds.push(true)
+
+			queue = append(queue, loudsItem[V]{node: node, pos: item.pos + 1})
+			l.labels = append(l.labels, node.label[item.pos])
+		} else {
+			for i := range node.ChildrenCount() {
+				child := node.childAt(i)
+				l.louds.push(true)
+
+				queue = append(queue, loudsItem[V]{node: child, pos: 1})
+				l.labels = append(l.labels, child.label[0])
+			}
+		}
+
+		l.louds.push(false)
+		l.terminal.push(item.pos == len(node.label) && node.isEndOfKey)
+
+		if uint64(l.louds.length) > bitVectorMaxLength {
+			return nil, fmt.Errorf("%w: %d keys", ErrLOUDSTooLarge, t.Len())
+		}
+
+		// 処理済みの要素を解放するため、キューの前半が空いたら詰める
+		if head >= len(queue)/2 && head >= 1024 {
+			queue = append(queue[:0], queue[head+1:]...)
+			head = -1
+		}
+	}
+
+	l.louds.buildIndex()
+	l.terminal.buildIndex()
+	l.labels = slices.Clone(l.labels)
+
+	return l, nil
+}
+
+// Len 格納されているキーの数を取得
+func (l *LOUDSTrie) Len() int {
+	return int(l.terminal.ranks[len(l.terminal.ranks)-1])
+}
+
+// children ノードiの子ノードの番号の範囲[first, end)
+// ノードiの子の並びは(i)番目の0の直後から次の0までで、その前にある1の数が最初の子の番号になる
+func (l *LOUDSTrie) children(i int) (int, int) {
+	start := l.louds.select0(i) + 1
+	end := l.louds.nextZero(start)
+	first := start - i - 1
+
+	return first, first + end - start
+}
+
+// parent ノードj（根以外）の親ノードの番号
+// ノードjに対応する1の前にある0の数から、仮想的な根の親の分を引いたもの
+func (l *LOUDSTrie) parent(j int) int {
+	return l.louds.select1(j) - j - 1
+}
+
+// child ノードiの子ノードのうち、辺のラベルがbのものの番号を取得
+func (l *LOUDSTrie) child(i int, b byte) (int, bool) {
+	first, end := l.children(i)
+
+	j, found := slices.BinarySearch(l.labels[first:end], b)
+
+	return first + j, found
+}
+
+// findNode キーまたはプレフィックスの末尾に対応するノードを取得
+func (l *LOUDSTrie) findNode(key string) (int, bool) {
+	node := 0
+
+	for i := range len(key) {
+		next, ok := l.child(node, key[i])
+		if !ok {
+			return 0, false
+		}
+
+		node = next
+	}
+
+	return node, true
+}
+
+// Search キーが存在するかを検索
+func (l *LOUDSTrie) Search(key string) bool {
+	_, found := l.KeyID(key)
+
+	return found
+}
+
+// KeyID キーに対応するキーIDを取得
+func (l *LOUDSTrie) KeyID(key string) (int, bool) {
+	node, ok := l.findNode(key)
+	if !ok || !l.terminal.get(node) {
+		return 0, false
+	}
+
+	return l.terminal.rank1(node), true
+}
+
+// Key キーIDに対応するキーを取得（終端ノードから根まで辺のラベルを辿って復元する）
+func (l *LOUDSTrie) Key(id int) (string, bool) {
+	if id < 0 || id >= l.Len() {
+		return "", false
+	}
+
+	var key []byte
+
+	for node := l.terminal.select1(id); node != 0; node = l.parent(node) {
+		key = append(key, l.labels[node])
+	}
+
+	slices.Reverse(key)
+
+	return string(key), true
+}
+
+// FindByPrefix 指定されたプレフィックスを持つすべてのキーをバイト順の昇順で検索
+func (l *LOUDSTrie) FindByPrefix(prefix string) []string {
+	var result []string
+
+	for key := range l.WithPrefix(prefix) {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+// WithPrefix 指定されたプレフィックスを持つキーとキーIDをバイト順の昇順で遅延列挙するイテレータを取得
+func (l *LOUDSTrie) WithPrefix(prefix string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		node, ok := l.findNode(prefix)
+		if !ok {
+			return
+		}
+
+		l.walk(node, prefix, func(key []byte, id int) bool {
+			return yield(string(key), id)
+		})
+	}
+}
+
+// loudsFrame 走査中のノードの次に辿る子ノードと子ノードの終わりの番号、ノードまでのキーの長さ
+type loudsFrame struct {
+	next, end int
+	keyLen    int
+}
+
+// walk 指定されたノード以下のキーとキーIDを昇順に走査（中断された場合はfalseを返す）
+func (l *LOUDSTrie) walk(node int, prefix string, fn func(key []byte, id int) bool) bool {
+	key := []byte(prefix)
+
+	if l.terminal.get(node) && !fn(key, l.terminal.rank1(node)) {
+		return false
+	}
+
+	first, end := l.children(node)
+	stack := []loudsFrame{{next: first, end: end, keyLen: len(key)}}
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == top.end {
+			stack = stack[:len(stack)-1]
+
+			continue
+		}
+
+		child := top.next
+		top.next++
+
+		key = append(key[:top.keyLen], l.labels[child])
+
+		if l.terminal.get(child) && !fn(key, l.terminal.rank1(child)) {
+			return false
+		}
+
+		first, end := l.children(child)
+		if first < end {
+			stack = append(stack, loudsFrame{next: first, end: end, keyLen: len(key)})
+		}
+	}
+
+	return true
+}
+
+// sizeInBytes ビット列・補助索引・辺のラベルの合計の大きさ（バイト数）
+func (l *LOUDSTrie) sizeInBytes() int {
+	return l.louds.sizeInBytes() + l.terminal.sizeInBytes() + len(l.labels)
+}
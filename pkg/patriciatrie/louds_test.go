@@ -0,0 +1,131 @@
+package patriciatrie
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_ToLOUDS(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"", "cat", "cats", "car", "dog", "do", "東京", "東京都"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	louds, err := trie.ToLOUDS()
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, louds.Len())
+	assert.True(t, louds.Search(""))
+	assert.True(t, louds.Search("東京都"))
+	assert.False(t, louds.Search("ca"))
+	assert.False(t, louds.Search("catsup"))
+
+	assert.Equal(t, []string{"car", "cat", "cats"}, louds.FindByPrefix("ca"))
+	assert.Equal(t, []string{"東京", "東京都"}, louds.FindByPrefix("東"))
+	assert.Empty(t, louds.FindByPrefix("x"))
+
+	// キーIDは長さ順（同じ長さではバイト順）
+	for id, key := range []string{"", "do", "car", "cat", "dog", "cats", "東京", "東京都"} {
+		gotID, found := louds.KeyID(key)
+		assert.True(t, found, key)
+		assert.Equal(t, id, gotID, key)
+
+		gotKey, found := louds.Key(id)
+		assert.True(t, found, id)
+		assert.Equal(t, key, gotKey, id)
+	}
+
+	_, found := louds.KeyID("ca")
+	assert.False(t, found)
+
+	_, found = louds.Key(8)
+	assert.False(t, found)
+
+	_, found = louds.Key(-1)
+	assert.False(t, found)
+}
+
+func TestTrie_ToLOUDS_MatchesTrie(t *testing.T) {
+	t.Parallel()
+
+	// 0x00から0xffまでのすべてのバイトを辺のラベルに使う
+	alphabet := make([]byte, 256)
+	for i := range alphabet {
+		alphabet[i] = byte(i)
+	}
+
+	trie := newRandomIntTrie(t, 1, 5000, string(alphabet))
+
+	louds, err := trie.ToLOUDS()
+	require.NoError(t, err)
+
+	assert.Equal(t, trie.Len(), louds.Len())
+	assert.Equal(t, slices.Collect(trie.Keys()), louds.FindByPrefix(""))
+
+	// すべてのキーIDが0からLen()-1に1対1で対応する
+	seen := make([]bool, louds.Len())
+
+	for key := range trie.Keys() {
+		id, found := louds.KeyID(key)
+		require.True(t, found, key)
+		assert.False(t, seen[id], key)
+		seen[id] = true
+
+		restored, found := louds.Key(id)
+		assert.True(t, found, id)
+		assert.Equal(t, key, restored, id)
+	}
+
+	for key, id := range louds.WithPrefix("") {
+		gotID, _ := louds.KeyID(key)
+		assert.Equal(t, gotID, id, key)
+	}
+
+	rng := rand.New(rand.NewSource(2))
+
+	for range 200 {
+		query := string([]byte{byte(rng.Intn(256)), byte(rng.Intn(256))})[:rng.Intn(3)]
+		assert.Equal(t, trie.Search(query), louds.Search(query), query)
+		assert.Equal(t, trie.FindByPrefix(query), louds.FindByPrefix(query), query)
+	}
+}
+
+func TestTrie_ToLOUDS_Empty(t *testing.T) {
+	t.Parallel()
+
+	louds, err := New().ToLOUDS()
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, louds.Len())
+	assert.False(t, louds.Search(""))
+	assert.Empty(t, louds.FindByPrefix(""))
+
+	_, found := louds.Key(0)
+	assert.False(t, found)
+}
+
+func TestTrie_ToLOUDS_Compact(t *testing.T) {
+	t.Parallel()
+
+	keys := generateLargeRandomKeys(20000, 12)
+
+	trie := New()
+	for _, key := range keys {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	louds, err := trie.ToLOUDS()
+	require.NoError(t, err)
+
+	// ノード（辺のラベルのバイト）あたり、ラベル8ビット・LOUDS2ビット・終端1ビットと索引のみ
+	bitsPerNode := float64(louds.sizeInBytes()*8) / float64(len(louds.labels))
+	assert.Less(t, bitsPerNode, 13.0)
+}
@@ -0,0 +1,123 @@
+package patriciatrie
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// MappedTrie WriteFlatで書き出したファイルをメモリマップし、ノードを復元せずに直接検索する読み取り専用のトライ
+// 同じファイルを開いた複数のプロセスはページキャッシュ上の1つのコピーを共有する
+type MappedTrie struct {
+	flat  *flatTrie
+	unmap func() error
+}
+
+// OpenMapped WriteFlatで書き出したファイルをメモリマップして開く（使い終わったらCloseを呼ぶ）
+// 開く時にチェックサムと構造を検証するため、ファイル全体を1度読む
+func OpenMapped(path string) (*MappedTrie, error) {
+	file, err := os.Open(path) // #nosec G304 - 呼び出し元が指定したファイルを読み取り専用で開く
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	defer func() {
+		// マッピングはファイルを閉じた後も有効
+		_ = file.Close()
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+
+	if info.Size() < flatHeaderSize {
+		return nil, fmt.Errorf("%w: %d bytes is too short", ErrInvalidFormat, info.Size())
+	}
+
+	data, unmap, err := mapFile(file, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := parseFlat(data)
+	if err != nil {
+		return nil, errors.Join(err, unmap())
+	}
+
+	return &MappedTrie{flat: flat, unmap: unmap}, nil
+}
+
+// closedFlat 閉じたMappedTrieが参照する、キーを持たない根ノードのみのフラット形式のトライ
+var closedFlat = func() *flatTrie {
+	flat, _, _ := buildFlat(NewNode[any](""))
+
+	return flat
+}()
+
+// Close メモリマップを解放（以降は空のトライとして振る舞い、検索は見つからない結果を返す）
+// 検索と並行して呼び出してはならない
+func (m *MappedTrie) Close() error {
+	if m.unmap == nil {
+		return nil
+	}
+
+	err := m.unmap()
+	m.flat = closedFlat
+	m.unmap = nil
+
+	return err
+}
+
+// Len 格納されているキーの数を取得
+func (m *MappedTrie) Len() int {
+	return m.flat.count(0)
+}
+
+// Search キーが存在するかを検索
+func (m *MappedTrie) Search(key string) bool {
+	_, found := m.flat.search(key)
+
+	return found
+}
+
+// CountPrefix 指定されたプレフィックスを持つキーの数を取得
+func (m *MappedTrie) CountPrefix(prefix string) int {
+	node, _, found := m.flat.findPrefixNode(prefix)
+	if !found {
+		return 0
+	}
+
+	return m.flat.count(node)
+}
+
+// FindByPrefix 指定されたプレフィックスを持つすべてのキーをバイト順の昇順で検索
+func (m *MappedTrie) FindByPrefix(prefix string) []string {
+	var result []string
+
+	node, keyStart, found := m.flat.findPrefixNode(prefix)
+	if !found {
+		return result
+	}
+
+	m.flat.walk(node, prefix[:keyStart], Ascending, nil, func(key []byte, _ int) bool {
+		result = append(result, string(key))
+
+		return true
+	})
+
+	return result
+}
+
+// LongestPrefixOf 文字列sのプレフィックスになっている最長のキーを取得
+func (m *MappedTrie) LongestPrefixOf(s string) (string, bool) {
+	length, found := 0, false
+
+	m.flat.visitPrefixesOf(s, func(n, _ int) bool {
+		length, found = n, true
+
+		return true
+	})
+
+	return s[:length], found
+}
@@ -0,0 +1,227 @@
+package patriciatrie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFlatFile トライをフラット形式で一時ファイルに書き出してパスを返す
+func writeFlatFile[V any](t *testing.T, trie *Trie[V]) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	written, err := trie.WriteFlat(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+
+	path := filepath.Join(t.TempDir(), "trie.flat")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	return path
+}
+
+func TestOpenMapped(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"", "cat", "cats", "car", "dog", "do", "東京", "東京都"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	mapped, err := OpenMapped(writeFlatFile(t, trie))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, mapped.Close())
+	}()
+
+	assert.Equal(t, 8, mapped.Len())
+	assert.True(t, mapped.Search(""))
+	assert.True(t, mapped.Search("東京都"))
+	assert.False(t, mapped.Search("ca"))
+	assert.False(t, mapped.Search("catsup"))
+
+	assert.Equal(t, []string{"car", "cat", "cats"}, mapped.FindByPrefix("ca"))
+	assert.Equal(t, []string{"東京", "東京都"}, mapped.FindByPrefix("東"))
+	assert.Empty(t, mapped.FindByPrefix("x"))
+	assert.Equal(t, 3, mapped.CountPrefix("ca"))
+
+	key, found := mapped.LongestPrefixOf("東京都庁")
+	assert.True(t, found)
+	assert.Equal(t, "東京都", key)
+
+	// 空文字列のキーがあるため、一致しない入力でも空文字列が最長一致
+	key, found = mapped.LongestPrefixOf("xyz")
+	assert.True(t, found)
+	assert.Empty(t, key)
+}
+
+func TestOpenMapped_MatchesTrie(t *testing.T) {
+	t.Parallel()
+
+	trie := newRandomIntTrie(t, 1, 3000, "abcde")
+
+	mapped, err := OpenMapped(writeFlatFile(t, trie))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, mapped.Close())
+	}()
+
+	assert.Equal(t, trie.Len(), mapped.Len())
+	assert.Equal(t, slices.Collect(trie.Keys()), mapped.FindByPrefix(""))
+
+	for _, query := range []string{"", "a", "ab", "abc", "e", "eeee", "abcdeabcde", "x"} {
+		assert.Equal(t, trie.Search(query), mapped.Search(query), query)
+		assert.Equal(t, trie.FindByPrefix(query), mapped.FindByPrefix(query), query)
+		assert.Equal(t, trie.CountPrefix(query), mapped.CountPrefix(query), query)
+
+		wantKey, _, wantFound := trie.LongestPrefixOf(query)
+		gotKey, gotFound := mapped.LongestPrefixOf(query)
+		assert.Equal(t, wantFound, gotFound, query)
+		assert.Equal(t, wantKey, gotKey, query)
+	}
+}
+
+//nolint:paralleltest // AllocsPerRun must not run in parallel
+func TestMappedTrie_SearchDoesNotAllocate(t *testing.T) {
+	trie := New()
+	for _, key := range []string{"cat", "cats", "car", "dog"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	mapped, err := OpenMapped(writeFlatFile(t, trie))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, mapped.Close())
+	}()
+
+	// 検索はマップしたバイト列を直接参照し、アロケーションしない
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = mapped.Search("cats")
+		_ = mapped.CountPrefix("ca")
+		_, _ = mapped.LongestPrefixOf("category")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestOpenMapped_Empty(t *testing.T) {
+	t.Parallel()
+
+	mapped, err := OpenMapped(writeFlatFile(t, New()))
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, mapped.Len())
+	assert.False(t, mapped.Search(""))
+	assert.Empty(t, mapped.FindByPrefix(""))
+
+	_, found := mapped.LongestPrefixOf("a")
+	assert.False(t, found)
+
+	require.NoError(t, mapped.Close())
+	require.NoError(t, mapped.Close())
+}
+
+func TestMappedTrie_UseAfterClose(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"", "cat", "cats", "dog"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	mapped, err := OpenMapped(writeFlatFile(t, trie))
+	require.NoError(t, err)
+	require.NoError(t, mapped.Close())
+
+	// 解放したマッピングは参照せず、空のトライとして振る舞う
+	assert.Equal(t, 0, mapped.Len())
+	assert.False(t, mapped.Search("cat"))
+	assert.False(t, mapped.Search(""))
+	assert.Empty(t, mapped.FindByPrefix("ca"))
+	assert.Zero(t, mapped.CountPrefix(""))
+
+	_, found := mapped.LongestPrefixOf("cats")
+	assert.False(t, found)
+}
+
+func TestOpenMapped_RejectsCorruptFile(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"cat", "cats", "car", "dog"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+
+	_, err := trie.WriteFlat(&buf)
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+		target  error
+	}{
+		{"マジックの不一致", func(data []byte) []byte {
+			data[0] = 'X'
+
+			return data
+		}, ErrInvalidFormat},
+		{"未対応のバージョン", func(data []byte) []byte {
+			binary.LittleEndian.PutUint16(data[4:], flatVersion+1)
+
+			return data
+		}, ErrUnsupportedVersion},
+		{"途中で切れたファイル", func(data []byte) []byte {
+			return data[:len(data)-3]
+		}, ErrInvalidFormat},
+		{"ラベルの破損", func(data []byte) []byte {
+			data[len(data)-5] ^= 0xff
+
+			return data
+		}, ErrInvalidFormat},
+		{"子ノードの範囲外参照（チェックサムは正しい）", func(data []byte) []byte {
+			binary.LittleEndian.PutUint32(data[flatHeaderSize+4:], 100)
+
+			return resum(data)
+		}, ErrInvalidFormat},
+		{"兄弟ノードの順序の逆転（チェックサムは正しい）", func(data []byte) []byte {
+			nodeCount := int(binary.LittleEndian.Uint32(data[8:]))
+			firstBytes := data[flatHeaderSize+nodeCount*flatNodeSize:]
+			firstBytes[1], firstBytes[2] = firstBytes[2], firstBytes[1]
+
+			return resum(data)
+		}, ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "trie.flat")
+			require.NoError(t, os.WriteFile(path, tt.corrupt(slices.Clone(data)), 0o600))
+
+			_, err := OpenMapped(path)
+			require.ErrorIs(t, err, tt.target)
+		})
+	}
+
+	_, err = OpenMapped(filepath.Join(t.TempDir(), "missing.flat"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,103 @@
+package patriciatrie
+
+import "iter"
+
+// PrefixMatch 共通プレフィックス検索で見つかったキー
+type PrefixMatch[V any] struct {
+	// 一致したキー（入力文字列の先頭Lengthバイト）
+	Key string
+
+	// 一致したキーの長さ（バイト数）
+	Length int
+
+	// キーに対応する値
+	Value V
+}
+
+// LongestPrefixOf sのプレフィックスとなる格納済みキーのうち最長のものと、その値を取得
+func (t *Trie[V]) LongestPrefixOf(s string) (string, V, bool) {
+	var (
+		value  V
+		length int
+		found  bool
+	)
+
+	t.visitPrefixesOf(s, func(n int, node *Node[V]) bool {
+		length, value, found = n, node.value, true
+
+		return true
+	})
+
+	return s[:length], value, found
+}
+
+// ShortestPrefixOf sのプレフィックスとなる格納済みキーのうち最短のものと、その値を取得
+// 降下中に最初に見つかった終端ノードで探索を打ち切る
+func (t *Trie[V]) ShortestPrefixOf(s string) (string, V, bool) {
+	var (
+		value  V
+		length int
+		found  bool
+	)
+
+	t.visitPrefixesOf(s, func(n int, node *Node[V]) bool {
+		length, value, found = n, node.value, true
+
+		return false
+	})
+
+	return s[:length], value, found
+}
+
+// PrefixesOf sのプレフィックスとなる格納済みキーをすべて短い順に取得（共通プレフィックス検索）
+func (t *Trie[V]) PrefixesOf(s string) []PrefixMatch[V] {
+	var result []PrefixMatch[V]
+
+	t.visitPrefixesOf(s, func(n int, node *Node[V]) bool {
+		result = append(result, PrefixMatch[V]{Key: s[:n], Length: n, Value: node.value})
+
+		return true
+	})
+
+	return result
+}
+
+// PrefixesOfSeq sのプレフィックスとなる格納済みキーと値を短い順に遅延列挙するイテレータを取得
+func (t *Trie[V]) PrefixesOfSeq(s string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.visitPrefixesOf(s, func(n int, node *Node[V]) bool {
+			return yield(s[:n], node.value)
+		})
+	}
+}
+
+// visitPrefixesOf sのプレフィックスとなる格納済みキーの終端ノードを短い順に訪問
+// fnにはキーの長さと終端ノードが渡され、falseを返すと訪問を中断
+func (t *Trie[V]) visitPrefixesOf(s string, fn func(n int, node *Node[V]) bool) {
+	node := t.root
+	consumed := 0
+
+	for {
+		if node.isEndOfKey && !fn(consumed, node) {
+			return
+		}
+
+		if consumed == len(s) {
+			return
+		}
+
+		child, exists := node.GetChild(s[consumed])
+		if !exists {
+			return
+		}
+
+		// ラベル全体がsの残りと一致しない場合、これより長いプレフィックスは存在しない
+		remaining := s[consumed:]
+		if len(remaining) < len(child.label) || remaining[:len(child.label)] != child.label {
+			return
+		}
+
+		consumed += len(child.label)
+		node = child
+	}
+}
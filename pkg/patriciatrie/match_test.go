@@ -0,0 +1,182 @@
+package patriciatrie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_LongestPrefixOf(t *testing.T) {
+	t.Parallel()
+
+	// ルーティングテーブル風のデータ
+	trie := NewTrie[string]()
+	routes := map[string]string{
+		"10.":        "private-a",
+		"10.1.":      "office",
+		"10.1.2.":    "lab",
+		"192.168.":   "home",
+		"192.168.1.": "living",
+	}
+
+	for prefix, name := range routes {
+		_, err := trie.Put(prefix, name)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedKey   string
+		expectedValue string
+		expectedOK    bool
+	}{
+		{"最長一致", "10.1.2.3", "10.1.2.", "lab", true},
+		{"中間のキーに一致", "10.1.9.9", "10.1.", "office", true},
+		{"短いキーのみ一致", "10.200.0.1", "10.", "private-a", true},
+		{"入力とキーが完全一致", "192.168.1.", "192.168.1.", "living", true},
+		{"ラベル途中で不一致", "192.169.0.1", "", "", false},
+		{"一致なし", "172.16.0.1", "", "", false},
+		{"空文字列", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, value, ok := trie.LongestPrefixOf(tt.input)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedKey, key)
+			assert.Equal(t, tt.expectedValue, value)
+		})
+	}
+}
+
+func TestTrie_LongestPrefixOf_EmptyKey(t *testing.T) {
+	t.Parallel()
+
+	trie := NewTrie[int]()
+	_, err := trie.Put("", 1)
+	require.NoError(t, err)
+	_, err = trie.Put("abc", 2)
+	require.NoError(t, err)
+
+	key, value, ok := trie.LongestPrefixOf("abd")
+	assert.True(t, ok)
+	assert.Empty(t, key)
+	assert.Equal(t, 1, value)
+
+	key, value, ok = trie.LongestPrefixOf("abcd")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", key)
+	assert.Equal(t, 2, value)
+}
+
+func TestTrie_ShortestPrefixOf(t *testing.T) {
+	t.Parallel()
+
+	// ブロックリスト風のデータ
+	trie := NewTrie[string]()
+	for _, entry := range []string{"ads.", "ads.example.", "tracker.example.com/", "tracker.example.com/pixel"} {
+		_, err := trie.Put(entry, "block:"+entry)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedKey   string
+		expectedValue string
+		expectedOK    bool
+	}{
+		{"最短一致", "ads.example.net", "ads.", "block:ads.", true},
+		{"唯一の一致", "tracker.example.com/pixel.gif", "tracker.example.com/", "block:tracker.example.com/", true},
+		{"ラベル途中で不一致", "tracker.example.org/", "", "", false},
+		{"一致なし", "www.example.com", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, value, ok := trie.ShortestPrefixOf(tt.input)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedKey, key)
+			assert.Equal(t, tt.expectedValue, value)
+		})
+	}
+}
+
+func TestTrie_PrefixesOf(t *testing.T) {
+	t.Parallel()
+
+	// 形態素解析の辞書風のデータ
+	trie := NewTrie[int]()
+	for i, word := range []string{"東", "東京", "東京都", "京都", "都"} {
+		_, err := trie.Put(word, i)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []PrefixMatch[int]
+	}{
+		{
+			name:  "複数の辞書語に一致",
+			input: "東京都に行く",
+			expected: []PrefixMatch[int]{
+				{Key: "東", Length: len("東"), Value: 0},
+				{Key: "東京", Length: len("東京"), Value: 1},
+				{Key: "東京都", Length: len("東京都"), Value: 2},
+			},
+		},
+		{
+			name:  "途中の位置から",
+			input: "京都府",
+			expected: []PrefixMatch[int]{
+				{Key: "京都", Length: len("京都"), Value: 3},
+			},
+		},
+		{
+			name:     "一致なし",
+			input:    "大阪",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, trie.PrefixesOf(tt.input))
+		})
+	}
+}
+
+func TestTrie_PrefixesOfSeq(t *testing.T) {
+	t.Parallel()
+
+	trie := NewTrie[int]()
+	for i, word := range []string{"a", "ab", "abc", "abcd"} {
+		_, err := trie.Put(word, i)
+		require.NoError(t, err)
+	}
+
+	var keys []string
+
+	var values []int
+
+	for key, value := range trie.PrefixesOfSeq("abcde") {
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if len(keys) == 3 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"a", "ab", "abc"}, keys)
+	assert.Equal(t, []int{0, 1, 2}, values)
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package patriciatrie
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// mapFile mmapを使えない環境では、ファイル全体をヒープに読み込んで代用する
+func mapFile(file *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+
+	_, err := io.ReadFull(file, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read: %w", err)
+	}
+
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package patriciatrie
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mapFile ファイル全体を読み取り専用で共有マッピングし、マッピングと解放関数を返す
+// 同じファイルを開いた複数のプロセスはページキャッシュ上の1つのコピーを共有する
+func mapFile(file *os.File, size int) ([]byte, func() error, error) {
+	data, err := syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED) //nolint:gosec // ファイル記述子は非負
+	if err != nil {
+		return nil, nil, fmt.Errorf("mmap: %w", err)
+	}
+
+	unmap := func() error {
+		err := syscall.Munmap(data)
+		if err != nil {
+			return fmt.Errorf("munmap: %w", err)
+		}
+
+		return nil
+	}
+
+	return data, unmap, nil
+}
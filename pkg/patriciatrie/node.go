@@ -1,60 +1,308 @@
 package patriciatrie
 
-// Node パトリシアトライのノード構造体
-type Node struct {
+import "slices"
+
+// nodeKind 子ノードの索引の形式（ART: Adaptive Radix Treeの4/16/48/256分岐ノード）
+type nodeKind uint8
+
+const (
+	// node4 最大4つの子ノードをソート済み配列で保持し、線形探索で引く
+	node4 nodeKind = iota
+	// node16 最大16の子ノードをソート済み配列で保持し、二分探索で引く
+	node16
+	// node48 最大48の子ノードをスロットに保持し、256要素のバイト索引からスロットを引く
+	node48
+	// node256 256要素のテーブルで子ノードを直接引く
+	node256
+)
+
+const (
+	// 各形式に格納できる子ノードの最大数
+	node4Capacity  = 4
+	node16Capacity = 16
+	node48Capacity = 48
+
+	// childTableSize node48の索引とnode256のテーブルの要素数（1バイトで表せる値の数）
+	childTableSize = 256
+
+	// 子ノードの数がこれ以下になったら1段小さい形式に縮小する（拡大直後の縮小の往復を防ぐため差を持たせる）
+	node16ShrinkThreshold  = 3
+	node48ShrinkThreshold  = 12
+	node256ShrinkThreshold = 40
+)
+
+// Node パトリシアトライのノード構造体（Vは終端ノードに格納する値の型）
+// 子ノードの索引は分岐数に応じてnode4/node16/node48/node256の形式を切り替える
+type Node[V any] struct {
 	// エッジラベル（パス圧縮された文字列）
 	label string
 
-	// 子ノードのマップ（最初の文字をキーとする）
-	children map[byte]*Node
+	// 子ノードのラベルの先頭バイト（すべての形式で昇順に保持）
+	childKeys []byte
+
+	// node4/node16: childKeysと同じ順序の子ノード
+	// node48: スロット（childSlotsの値-1で引く、順序なし）
+	// node256: 先頭バイトで直接引く256要素のテーブル
+	childNodes []*Node[V]
+
+	// node48: 先頭バイトから子ノードのスロット位置+1を引く索引（0は子ノードなし）
+	childSlots *[childTableSize]uint8
+
+	// 子ノードの索引の形式
+	kind nodeKind
 
 	// このノードがキーの終端かどうか
 	isEndOfKey bool
 
-	// 値（必要に応じて）
-	value interface{}
+	// このノードを根とする部分木に含まれるキーの数（自身を含む）
+	count int
+
+	// 値（終端ノードのみ有効）
+	value V
 }
 
-// NewNode 新しいノードを作成
-func NewNode(label string) *Node {
-	return &Node{
+// NewNode 新しいノードを作成（子ノード用の領域は最初の子の追加時に確保）
+func NewNode[V any](label string) *Node[V] {
+	return &Node[V]{
 		label:      label,
 		isEndOfKey: false,
-		children:   make(map[byte]*Node),
-		value:      nil,
 	}
 }
 
 // HasChild 指定されたバイトで始まる子ノードが存在するかチェック
-func (n *Node) HasChild(b byte) bool {
-	_, exists := n.children[b]
+func (n *Node[V]) HasChild(b byte) bool {
+	_, exists := n.GetChild(b)
 
 	return exists
 }
 
 // GetChild 指定されたバイトで始まる子ノードを取得
-func (n *Node) GetChild(b byte) (*Node, bool) {
-	child, exists := n.children[b]
+func (n *Node[V]) GetChild(b byte) (*Node[V], bool) {
+	switch n.kind {
+	case node4:
+		for i, key := range n.childKeys {
+			if key == b {
+				return n.childNodes[i], true
+			}
+		}
+
+		return nil, false
+	case node16:
+		i, exists := n.childIndex(b)
+		if !exists {
+			return nil, false
+		}
+
+		return n.childNodes[i], true
+	case node48:
+		slot := n.childSlots[b]
+		if slot == 0 {
+			return nil, false
+		}
+
+		return n.childNodes[slot-1], true
+	default:
+		child := n.childNodes[b]
 
-	return child, exists
+		return child, child != nil
+	}
 }
 
-// AddChild 子ノードを追加
-func (n *Node) AddChild(b byte, child *Node) {
-	n.children[b] = child
+// AddChild 子ノードを追加（同じバイトの子ノードが既にある場合は置き換え）
+func (n *Node[V]) AddChild(b byte, child *Node[V]) {
+	i, exists := n.childIndex(b)
+	if exists {
+		n.replaceChild(i, b, child)
+
+		return
+	}
+
+	if len(n.childKeys) == n.capacity() {
+		n.grow()
+	}
+
+	n.childKeys = slices.Insert(n.childKeys, i, b)
+
+	switch n.kind {
+	case node4, node16:
+		n.childNodes = slices.Insert(n.childNodes, i, child)
+	case node48:
+		slot := slices.Index(n.childNodes, nil)
+		n.childNodes[slot] = child
+		n.childSlots[b] = uint8(slot + 1) //nolint:gosec // スロット位置は48未満
+	default:
+		n.childNodes[b] = child
+	}
 }
 
 // RemoveChild 子ノードを削除
-func (n *Node) RemoveChild(b byte) {
-	delete(n.children, b)
+func (n *Node[V]) RemoveChild(b byte) {
+	i, exists := n.childIndex(b)
+	if !exists {
+		return
+	}
+
+	n.childKeys = slices.Delete(n.childKeys, i, i+1)
+
+	switch n.kind {
+	case node4, node16:
+		n.childNodes = slices.Delete(n.childNodes, i, i+1)
+	case node48:
+		n.childNodes[n.childSlots[b]-1] = nil
+		n.childSlots[b] = 0
+	default:
+		n.childNodes[b] = nil
+	}
+
+	n.shrink()
 }
 
 // IsLeaf このノードが葉ノードかどうかチェック
-func (n *Node) IsLeaf() bool {
-	return len(n.children) == 0
+func (n *Node[V]) IsLeaf() bool {
+	return len(n.childKeys) == 0
 }
 
 // ChildrenCount 子ノードの数を取得
-func (n *Node) ChildrenCount() int {
-	return len(n.children)
+func (n *Node[V]) ChildrenCount() int {
+	return len(n.childKeys)
+}
+
+// childIndex 先頭バイトbの子ノードの昇順での位置を取得（存在しない場合は挿入すべき位置）
+func (n *Node[V]) childIndex(b byte) (int, bool) {
+	return slices.BinarySearch(n.childKeys, b)
+}
+
+// childAt 先頭バイトの昇順でi番目の子ノードを取得
+func (n *Node[V]) childAt(i int) *Node[V] {
+	switch n.kind {
+	case node4, node16:
+		return n.childNodes[i]
+	case node48:
+		return n.childNodes[n.childSlots[n.childKeys[i]]-1]
+	default:
+		return n.childNodes[n.childKeys[i]]
+	}
+}
+
+// setChildren 子ノードのない状態から、先頭バイトの昇順に並んだ子ノードをまとめて設定
+// 一括構築で使い、子ノードの数に合った形式の索引を必要な大きさで1度だけ確保する
+func (n *Node[V]) setChildren(children []*Node[V]) {
+	if len(children) == 0 {
+		return
+	}
+
+	n.childKeys = make([]byte, len(children))
+	for i, child := range children {
+		n.childKeys[i] = child.label[0]
+	}
+
+	n.childNodes = slices.Clone(children)
+	n.kind = node4
+
+	switch {
+	case len(children) > node48Capacity:
+		n.toNode256()
+	case len(children) > node16Capacity:
+		n.toNode48()
+	case len(children) > node4Capacity:
+		n.kind = node16
+	}
+}
+
+// replaceChild 昇順でi番目（先頭バイトb）の子ノードを置き換え
+func (n *Node[V]) replaceChild(i int, b byte, child *Node[V]) {
+	switch n.kind {
+	case node4, node16:
+		n.childNodes[i] = child
+	case node48:
+		n.childNodes[n.childSlots[b]-1] = child
+	default:
+		n.childNodes[b] = child
+	}
+}
+
+// capacity 現在の形式に格納できる子ノードの最大数
+func (n *Node[V]) capacity() int {
+	switch n.kind {
+	case node4:
+		return node4Capacity
+	case node16:
+		return node16Capacity
+	case node48:
+		return node48Capacity
+	default:
+		return childTableSize
+	}
+}
+
+// grow 子ノードの索引を1段大きい形式に拡大
+func (n *Node[V]) grow() {
+	switch n.kind {
+	case node4:
+		// 配列はそのまま使い、領域は追加時に必要な分だけ伸ばす
+		n.kind = node16
+	case node16:
+		n.toNode48()
+	case node48:
+		n.toNode256()
+	case node256:
+	}
+}
+
+// shrink 子ノードの数が減った場合、子ノードの索引を1段小さい形式に縮小
+func (n *Node[V]) shrink() {
+	count := len(n.childKeys)
+
+	switch {
+	case n.kind == node16 && count <= node16ShrinkThreshold:
+		n.kind = node4
+		n.childKeys = slices.Clip(n.childKeys)
+		n.childNodes = slices.Clip(n.childNodes)
+	case n.kind == node48 && count <= node48ShrinkThreshold:
+		n.toNode16()
+	case n.kind == node256 && count <= node256ShrinkThreshold:
+		n.toNode48()
+	case count == 0:
+		// 葉ノードになった場合は子ノード用の領域を解放
+		*n = Node[V]{label: n.label, isEndOfKey: n.isEndOfKey, count: n.count, value: n.value}
+	}
+}
+
+// toNode48 子ノードをnode48のスロットに移す
+func (n *Node[V]) toNode48() {
+	slots := new([childTableSize]uint8)
+	nodes := make([]*Node[V], node48Capacity)
+
+	for i, b := range n.childKeys {
+		nodes[i] = n.childAt(i)
+		slots[b] = uint8(i + 1) //nolint:gosec // スロット位置は48未満
+	}
+
+	n.childNodes = nodes
+	n.childSlots = slots
+	n.kind = node48
+}
+
+// toNode256 子ノードをnode256のテーブルに移す
+func (n *Node[V]) toNode256() {
+	table := make([]*Node[V], childTableSize)
+	for i, b := range n.childKeys {
+		table[b] = n.childAt(i)
+	}
+
+	n.childNodes = table
+	n.childSlots = nil
+	n.kind = node256
+}
+
+// toNode16 子ノードをnode16のソート済み配列に移す
+func (n *Node[V]) toNode16() {
+	nodes := make([]*Node[V], len(n.childKeys))
+	for i := range n.childKeys {
+		nodes[i] = n.childAt(i)
+	}
+
+	n.childNodes = nodes
+	n.childSlots = nil
+	n.kind = node16
 }
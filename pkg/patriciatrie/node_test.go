@@ -4,18 +4,19 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewNode(t *testing.T) {
 	t.Parallel()
 
 	label := "test"
-	node := NewNode(label)
+	node := NewNode[any](label)
 
 	assert.NotNil(t, node)
 	assert.Equal(t, label, node.label)
-	assert.NotNil(t, node.children)
-	assert.Empty(t, node.children)
+	assert.True(t, node.IsLeaf())
+	assert.Equal(t, 0, node.ChildrenCount())
 	assert.False(t, node.isEndOfKey)
 	assert.Nil(t, node.value)
 }
@@ -23,13 +24,13 @@ func TestNewNode(t *testing.T) {
 func TestNode_HasChild(t *testing.T) {
 	t.Parallel()
 
-	node := NewNode("test")
+	node := NewNode[any]("test")
 
 	// 子ノードが存在しない場合
 	assert.False(t, node.HasChild('a'))
 
 	// 子ノードを追加
-	child := NewNode("child")
+	child := NewNode[any]("child")
 	node.AddChild('a', child)
 
 	// 子ノードが存在する場合
@@ -40,8 +41,8 @@ func TestNode_HasChild(t *testing.T) {
 func TestNode_GetChild(t *testing.T) {
 	t.Parallel()
 
-	node := NewNode("test")
-	child := NewNode("child")
+	node := NewNode[any]("test")
+	child := NewNode[any]("child")
 	node.AddChild('a', child)
 
 	// 存在する子ノードを取得
@@ -58,42 +59,57 @@ func TestNode_GetChild(t *testing.T) {
 func TestNode_AddChild(t *testing.T) {
 	t.Parallel()
 
-	node := NewNode("test")
-	child := NewNode("child")
+	node := NewNode[any]("test")
+	child := NewNode[any]("child")
 
 	// 子ノードを追加
 	node.AddChild('a', child)
 
-	assert.Len(t, node.children, 1)
-	assert.Equal(t, child, node.children['a'])
+	assert.Equal(t, 1, node.ChildrenCount())
+
+	result, exists := node.GetChild('a')
+	assert.True(t, exists)
+	assert.Equal(t, child, result)
+
+	// 同じバイトの子ノードは置き換え
+	replacement := NewNode[any]("another")
+	node.AddChild('a', replacement)
+
+	result, _ = node.GetChild('a')
+	assert.Equal(t, 1, node.ChildrenCount())
+	assert.Equal(t, replacement, result)
 }
 
 func TestNode_RemoveChild(t *testing.T) {
 	t.Parallel()
 
-	node := NewNode("test")
-	child := NewNode("child")
+	node := NewNode[any]("test")
+	child := NewNode[any]("child")
 	node.AddChild('a', child)
 
-	assert.Len(t, node.children, 1)
+	assert.Equal(t, 1, node.ChildrenCount())
 
 	// 子ノードを削除
 	node.RemoveChild('a')
 
-	assert.Empty(t, node.children)
+	assert.Equal(t, 0, node.ChildrenCount())
 	assert.False(t, node.HasChild('a'))
+
+	// 存在しない子ノードの削除は何もしない
+	node.RemoveChild('b')
+	assert.Equal(t, 0, node.ChildrenCount())
 }
 
 func TestNode_IsLeaf(t *testing.T) {
 	t.Parallel()
 
-	node := NewNode("test")
+	node := NewNode[any]("test")
 
 	// 子ノードがない場合は葉ノード
 	assert.True(t, node.IsLeaf())
 
 	// 子ノードを追加
-	child := NewNode("child")
+	child := NewNode[any]("child")
 	node.AddChild('a', child)
 
 	// 子ノードがある場合は葉ノードではない
@@ -103,17 +119,119 @@ func TestNode_IsLeaf(t *testing.T) {
 func TestNode_ChildrenCount(t *testing.T) {
 	t.Parallel()
 
-	node := NewNode("test")
+	node := NewNode[any]("test")
 
 	// 初期状態では子ノードは0個
 	assert.Equal(t, 0, node.ChildrenCount())
 
 	// 子ノードを追加
-	child1 := NewNode("child1")
-	child2 := NewNode("child2")
+	child1 := NewNode[any]("child1")
+	child2 := NewNode[any]("child2")
 
 	node.AddChild('a', child1)
 	node.AddChild('b', child2)
 
 	assert.Equal(t, 2, node.ChildrenCount())
 }
+
+func TestNode_ChildrenOrder(t *testing.T) {
+	t.Parallel()
+
+	node := NewNode[any]("")
+	for _, b := range []byte("dbca") {
+		node.AddChild(b, NewNode[any](string(b)))
+	}
+
+	labels := make([]string, 0, node.ChildrenCount())
+	for i := range node.ChildrenCount() {
+		labels = append(labels, node.childAt(i).label)
+	}
+
+	// 追加順に関わらず先頭バイトの昇順
+	assert.Equal(t, []string{"a", "b", "c", "d"}, labels)
+}
+
+func TestNode_KindTransitions(t *testing.T) {
+	t.Parallel()
+
+	node := NewNode[any]("")
+
+	// 子ノードの数に応じてnode4→node16→node48→node256と拡大する
+	grows := []struct {
+		count int
+		kind  nodeKind
+	}{
+		{node4Capacity, node4},
+		{node4Capacity + 1, node16},
+		{node16Capacity, node16},
+		{node16Capacity + 1, node48},
+		{node48Capacity, node48},
+		{node48Capacity + 1, node256},
+		{childTableSize, node256},
+	}
+
+	added := 0
+	for _, tt := range grows {
+		for ; added < tt.count; added++ {
+			// 降順に追加しても昇順を保つことを確認する
+			b := byte(255 - added)
+			node.AddChild(b, NewNode[any](string([]byte{b})))
+		}
+
+		assert.Equal(t, tt.kind, node.kind, "children=%d", tt.count)
+		assertChildren(t, node, tt.count)
+	}
+
+	// 縮小はしきい値を下回るまで行わない
+	shrinks := []struct {
+		count int
+		kind  nodeKind
+	}{
+		{node256ShrinkThreshold + 1, node256},
+		{node256ShrinkThreshold, node48},
+		{node48ShrinkThreshold + 1, node48},
+		{node48ShrinkThreshold, node16},
+		{node16ShrinkThreshold + 1, node16},
+		{node16ShrinkThreshold, node4},
+		{0, node4},
+	}
+
+	removed := 0
+	for _, tt := range shrinks {
+		for ; childTableSize-removed > tt.count; removed++ {
+			node.RemoveChild(byte(removed))
+		}
+
+		assert.Equal(t, tt.kind, node.kind, "children=%d", tt.count)
+		assertChildren(t, node, tt.count)
+	}
+
+	assert.True(t, node.IsLeaf())
+	assert.Nil(t, node.childNodes)
+	assert.Nil(t, node.childSlots)
+}
+
+// assertChildren 子ノードが先頭バイトの昇順に並び、GetChildで引けることを確認
+func assertChildren(t *testing.T, node *Node[any], count int) {
+	t.Helper()
+
+	require.Equal(t, count, node.ChildrenCount())
+
+	for i := range node.ChildrenCount() {
+		child := node.childAt(i)
+		assert.Equal(t, node.childKeys[i], child.label[0])
+
+		if i > 0 {
+			assert.Less(t, node.childKeys[i-1], node.childKeys[i])
+		}
+
+		found, exists := node.GetChild(child.label[0])
+		assert.True(t, exists)
+		assert.Same(t, child, found)
+	}
+
+	if count > 0 && count < childTableSize {
+		_, exists := node.GetChild(node.childKeys[0] - 1)
+		assert.False(t, exists)
+	}
+}
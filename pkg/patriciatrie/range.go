@@ -0,0 +1,41 @@
+package patriciatrie
+
+import "iter"
+
+// Bound 範囲検索の端点の扱い
+type Bound int
+
+const (
+	// Inclusive 端点のキーを範囲に含める
+	Inclusive Bound = iota
+	// Exclusive 端点のキーを範囲に含めない
+	Exclusive
+)
+
+// Range 半開区間[from, to)に含まれるキーと値をバイト順の昇順で遅延列挙するイテレータを取得
+func (t *Trie[V]) Range(from, to string) iter.Seq2[string, V] {
+	return t.RangeBounds(from, Inclusive, to, Exclusive)
+}
+
+// RangeBounds fromからtoまでのキーと値を、端点の扱いを指定してバイト順の昇順で遅延列挙するイテレータを取得
+func (t *Trie[V]) RangeBounds(from string, fromBound Bound, to string, toBound Bound) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		cursor := t.Cursor()
+
+		ok := cursor.Seek(from)
+		if ok && fromBound == Exclusive && string(cursor.key) == from {
+			ok = cursor.Next()
+		}
+
+		for ; ok; ok = cursor.Next() {
+			// 上端を超えたら終了（比較ではキー文字列を生成しない）
+			if string(cursor.key) > to || (toBound == Exclusive && string(cursor.key) == to) {
+				return
+			}
+
+			if !yield(cursor.Key(), cursor.Value()) {
+				return
+			}
+		}
+	}
+}
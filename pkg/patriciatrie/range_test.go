@@ -0,0 +1,127 @@
+package patriciatrie
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rangeTestKeys 範囲検索のテストで使用するキー
+var rangeTestKeys = []string{
+	"order/2026-09-30/001",
+	"order/2026-10-01/001",
+	"order/2026-10-01/002",
+	"order/2026-10-02/001",
+	"order/2026-10-03/001",
+	"user/1",
+}
+
+func TestTrie_Range(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, rangeTestKeys...)
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected []string
+	}{
+		{
+			name:     "日付の範囲",
+			from:     "order/2026-10-01/",
+			to:       "order/2026-10-03/",
+			expected: []string{"order/2026-10-01/001", "order/2026-10-01/002", "order/2026-10-02/001"},
+		},
+		{
+			name:     "上端は含まない",
+			from:     "order/2026-10-01/001",
+			to:       "order/2026-10-02/001",
+			expected: []string{"order/2026-10-01/001", "order/2026-10-01/002"},
+		},
+		{
+			name:     "空の範囲",
+			from:     "order/2026-10-05/",
+			to:       "order/2026-10-06/",
+			expected: nil,
+		},
+		{
+			name:     "逆転した範囲",
+			from:     "user/",
+			to:       "order/",
+			expected: nil,
+		},
+		{
+			name:     "全体を含む範囲",
+			from:     "",
+			to:       "z",
+			expected: slices.Collect(trie.Keys()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var keys []string
+
+			for key, value := range trie.Range(tt.from, tt.to) {
+				expected, _ := trie.Get(key)
+				assert.Equal(t, expected, value)
+
+				keys = append(keys, key)
+			}
+
+			assert.Equal(t, tt.expected, keys)
+		})
+	}
+}
+
+func TestTrie_RangeBounds(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "a", "b", "c", "d")
+
+	tests := []struct {
+		name      string
+		fromBound Bound
+		toBound   Bound
+		expected  []string
+	}{
+		{"閉区間", Inclusive, Inclusive, []string{"b", "c"}},
+		{"半開区間", Inclusive, Exclusive, []string{"b"}},
+		{"左半開区間", Exclusive, Inclusive, []string{"c"}},
+		{"開区間", Exclusive, Exclusive, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var keys []string
+			for key := range trie.RangeBounds("b", tt.fromBound, "c", tt.toBound) {
+				keys = append(keys, key)
+			}
+
+			assert.Equal(t, tt.expected, keys)
+		})
+	}
+}
+
+func TestTrie_RangeBreak(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, rangeTestKeys...)
+
+	var keys []string
+
+	for key := range trie.Range("order/", "order0") {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"order/2026-09-30/001", "order/2026-10-01/001"}, keys)
+}
@@ -0,0 +1,103 @@
+package patriciatrie
+
+import "strings"
+
+// Select バイト順の昇順でi番目（0始まり）のキーを取得
+func (t *Trie[V]) Select(i int) (string, bool) {
+	if i < 0 || i >= t.root.count {
+		return "", false
+	}
+
+	var key strings.Builder
+
+	node := t.root
+
+	for {
+		// 自身のキーは子孫のキーより前に並ぶ
+		if node.isEndOfKey {
+			if i == 0 {
+				return key.String(), true
+			}
+
+			i--
+		}
+
+		// 部分木のキー数を使ってi番目のキーを含む子ノードを選ぶ
+		var next *Node[V]
+
+		for j := range node.ChildrenCount() {
+			child := node.childAt(j)
+			if i < child.count {
+				next = child
+
+				break
+			}
+
+			i -= child.count
+		}
+
+		key.WriteString(next.label)
+		node = next
+	}
+}
+
+// Rank バイト順でkeyより前に並ぶキーの数を取得（keyが存在する場合はその順位と一致）
+func (t *Trie[V]) Rank(key string) int {
+	node := t.root
+	consumed := 0
+	rank := 0
+
+	for consumed < len(key) {
+		remaining := key[consumed:]
+
+		// 自身のキーはkeyの真のプレフィックスなのでkeyより前
+		if node.isEndOfKey {
+			rank++
+		}
+
+		next, skipped, descend := t.rankChild(node, remaining)
+		rank += skipped
+
+		if !descend {
+			return rank
+		}
+
+		consumed += len(next.label)
+		node = next
+	}
+
+	return rank
+}
+
+// rankChild remainingと比較して、より前に並ぶ子ノードのキー数と、降下すべき子ノードを取得
+// 子ノードのラベル全体がremainingのプレフィックスである場合のみdescendがtrueとなる
+func (t *Trie[V]) rankChild(node *Node[V], remaining string) (*Node[V], int, bool) {
+	skipped := 0
+
+	for i := range node.ChildrenCount() {
+		child := node.childAt(i)
+		if child.label[0] < remaining[0] {
+			skipped += child.count
+
+			continue
+		}
+
+		if child.label[0] > remaining[0] {
+			break
+		}
+
+		commonLen := t.findCommonPrefixLength(child.label, remaining)
+
+		switch {
+		case commonLen == len(child.label):
+			return child, skipped, true
+		case commonLen < len(remaining) && child.label[commonLen] < remaining[commonLen]:
+			// ラベルの途中でremainingより小さくなる場合、部分木全体が前に並ぶ
+			skipped += child.count
+		}
+
+		break
+	}
+
+	return nil, skipped, false
+}
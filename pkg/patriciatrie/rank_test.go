@@ -0,0 +1,88 @@
+package patriciatrie
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_Select(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	keys := []string{"dogs", "cat", "", "cats", "dog", "ca", "catalog", "b"}
+
+	for _, key := range keys {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	sorted := slices.Sorted(slices.Values(keys))
+
+	for i, expected := range sorted {
+		key, ok := trie.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, expected, key, "index %d", i)
+	}
+
+	_, ok := trie.Select(-1)
+	assert.False(t, ok)
+
+	_, ok = trie.Select(len(keys))
+	assert.False(t, ok)
+}
+
+func TestTrie_Rank(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"b", "ca", "cat", "catalog", "cats", "dog", "dogs"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected int
+	}{
+		{"先頭のキー", "b", 0},
+		{"存在するキー: cat", "cat", 2},
+		{"存在するキー: dogs", "dogs", 6},
+		{"空文字列", "", 0},
+		{"すべてのキーより前", "a", 0},
+		{"すべてのキーより後", "z", 7},
+		{"ラベル途中でcatalogより大きい", "catb", 4},
+		{"ラベル途中でcatalogより小さい", "cataa", 3},
+		{"catsより後", "catz", 5},
+		{"ラベルの途中で終わる", "cata", 3},
+		{"キーの間", "cb", 5},
+		{"既存キーより長い", "dogsled", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, trie.Rank(tt.key))
+		})
+	}
+}
+
+func TestTrie_RankSelectRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range generateRandomKeys(1000) {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	for i := range trie.Len() {
+		key, ok := trie.Select(i)
+		require.True(t, ok)
+		assert.Equal(t, i, trie.Rank(key))
+	}
+}
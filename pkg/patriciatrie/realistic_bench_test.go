@@ -36,7 +36,7 @@ func BenchmarkTrie_Japanese_Insert(b *testing.B) {
 			for range b.N {
 				trie := New()
 				for _, word := range words {
-					_ = trie.Insert(word)
+					_, _ = trie.Insert(word)
 				}
 			}
 
@@ -67,7 +67,7 @@ func BenchmarkTrie_Japanese_Search(b *testing.B) {
 
 			trie := New()
 			for _, word := range words {
-				_ = trie.Insert(word)
+				_, _ = trie.Insert(word)
 			}
 
 			b.ResetTimer()
@@ -102,7 +102,7 @@ func BenchmarkTrie_Japanese_PrefixSearch(b *testing.B) {
 
 			trie := New()
 			for _, word := range words {
-				_ = trie.Insert(word)
+				_, _ = trie.Insert(word)
 			}
 
 			// 様々な長さのプレフィックスを準備
@@ -126,6 +126,195 @@ func BenchmarkTrie_Japanese_PrefixSearch(b *testing.B) {
 	}
 }
 
+// BenchmarkTrie_Japanese_PrefixTop10 日本語データでイテレータによる先頭10件取得
+func BenchmarkTrie_Japanese_PrefixTop10(b *testing.B) {
+	const topN = 10
+
+	words, err := loadWordsFromFile("testdata/japanese/full.txt")
+	if err != nil {
+		b.Skipf("テストデータが見つかりません (make setup_benchmarkを実行してください)")
+	}
+
+	trie := New()
+	for _, word := range words {
+		_, _ = trie.Insert(word)
+	}
+
+	prefixes := generateJapanesePrefixes(words, 1, 1000)
+
+	b.Run("FindByPrefix", func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := range b.N {
+			results := trie.FindByPrefix(prefixes[i%len(prefixes)])
+			_ = results[:minValue(len(results), topN)]
+		}
+	})
+
+	b.Run("WithPrefix", func(b *testing.B) {
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for i := range b.N {
+			count := 0
+			for range trie.WithPrefix(prefixes[i%len(prefixes)]) {
+				count++
+				if count == topN {
+					break
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkTrie_Japanese_CommonPrefix 日本語データでの共通プレフィックス検索（形態素解析のラティス構築相当）
+func BenchmarkTrie_Japanese_CommonPrefix(b *testing.B) {
+	words, err := loadWordsFromFile("testdata/japanese/small.txt")
+	if err != nil {
+		b.Skipf("テストデータが見つかりません (make setup_benchmarkを実行してください)")
+	}
+
+	trie := New()
+	for _, word := range words {
+		_, _ = trie.Insert(word)
+	}
+
+	// 単語を連結して入力テキストとする
+	texts := make([]string, 0, 1000)
+	for i := 0; i+3 < len(words) && len(texts) < cap(texts); i += 3 {
+		texts = append(texts, words[i]+words[i+1]+words[i+2])
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := range b.N {
+		text := texts[i%len(texts)]
+		// テキストの各文字位置から共通プレフィックス検索
+		for pos := range text {
+			for range trie.PrefixesOfSeq(text[pos:]) {
+			}
+		}
+	}
+}
+
+// loadDoubleArrayFromFile setup_benchmark_data.shが出力した単語リストからダブル配列を構築
+func loadDoubleArrayFromFile(b *testing.B, filename string) *DoubleArray {
+	b.Helper()
+
+	file, err := openTestdata(filename)
+	if err != nil {
+		b.Skipf("テストデータが見つかりません: %s (make setup_benchmarkを実行してください)", filename)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	da, err := LoadDoubleArray(file)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return da
+}
+
+// BenchmarkTrie_Japanese_DoubleArray 日本語辞書データでのダブル配列とポインタ構造のトライの比較
+func BenchmarkTrie_Japanese_DoubleArray(b *testing.B) {
+	const file = "testdata/japanese/full.txt"
+
+	words, err := loadWordsFromFile(file)
+	if err != nil {
+		b.Skipf("テストデータが見つかりません: %s (make setup_benchmarkを実行してください)", file)
+	}
+
+	trie := New()
+	for _, word := range words {
+		_, _ = trie.Insert(word)
+	}
+
+	da := loadDoubleArrayFromFile(b, file)
+
+	b.Run("Build/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = FromSorted[any](words)
+		}
+	})
+
+	b.Run("Build/DoubleArray", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = DoubleArrayFromSorted(words)
+		}
+	})
+
+	b.Run("Search/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.Search(words[i%len(words)])
+		}
+	})
+
+	b.Run("Search/DoubleArray", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = da.Search(words[i%len(words)])
+		}
+	})
+
+	// 単語を連結して入力テキストとし、各文字位置から共通プレフィックス検索（形態素解析のラティス構築相当）
+	texts := make([]string, 0, 1000)
+	for i := 0; i+3 < len(words) && len(texts) < cap(texts); i += len(words)/cap(texts) + 1 {
+		texts = append(texts, words[i]+words[i+1]+words[i+2])
+	}
+
+	b.Run("CommonPrefix/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			text := texts[i%len(texts)]
+			for pos := range text {
+				for range trie.PrefixesOfSeq(text[pos:]) {
+				}
+			}
+		}
+	})
+
+	b.Run("CommonPrefix/DoubleArray", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			text := texts[i%len(texts)]
+			for pos := range text {
+				for range da.PrefixesOfSeq(text[pos:]) {
+				}
+			}
+		}
+	})
+
+	prefixes := generateJapanesePrefixes(words, 2, 1000)
+
+	b.Run("PrefixSearch/Trie", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = trie.FindByPrefix(prefixes[i%len(prefixes)])
+		}
+	})
+
+	b.Run("PrefixSearch/DoubleArray", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			_ = da.FindByPrefix(prefixes[i%len(prefixes)])
+		}
+	})
+}
+
 // BenchmarkTrie_IPv4_Insert IPv4アドレスでの挿入性能
 func BenchmarkTrie_IPv4_Insert(b *testing.B) {
 	datasets := []struct {
@@ -149,7 +338,7 @@ func BenchmarkTrie_IPv4_Insert(b *testing.B) {
 			for range b.N {
 				trie := New()
 				for _, ip := range ips {
-					_ = trie.Insert(ip)
+					_, _ = trie.Insert(ip)
 				}
 			}
 
@@ -177,7 +366,7 @@ func BenchmarkTrie_IPv4_Search(b *testing.B) {
 
 			trie := New()
 			for _, ip := range ips {
-				_ = trie.Insert(ip)
+				_, _ = trie.Insert(ip)
 			}
 
 			b.ResetTimer()
@@ -200,7 +389,7 @@ func BenchmarkTrie_IPv4_NetworkPrefix(b *testing.B) {
 
 	trie := New()
 	for _, ip := range ips {
-		_ = trie.Insert(ip)
+		_, _ = trie.Insert(ip)
 	}
 
 	// ネットワークプレフィックス（/8, /16, /24相当）を準備
@@ -229,6 +418,29 @@ func BenchmarkTrie_IPv4_NetworkPrefix(b *testing.B) {
 	}
 }
 
+// BenchmarkTrie_IPv4_LongestPrefix IPv4ネットワークプレフィックスに対する最長一致検索
+func BenchmarkTrie_IPv4_LongestPrefix(b *testing.B) {
+	ips, err := loadWordsFromFile("testdata/ipaddresses/ipv4_100k.txt")
+	if err != nil {
+		b.Skipf("テストデータが見つかりません (make setup_benchmarkを実行してください)")
+	}
+
+	// /8, /16, /24相当のプレフィックスをルーティングテーブルとして登録
+	trie := New()
+	for _, length := range []int{3, 7, 11} {
+		for _, prefix := range generateIPv4Prefixes(ips, length, 1000) {
+			_, _ = trie.Insert(prefix)
+		}
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := range b.N {
+		_, _, _ = trie.LongestPrefixOf(ips[i%len(ips)])
+	}
+}
+
 // BenchmarkTrie_IPv6_Insert IPv6アドレスでの挿入性能
 func BenchmarkTrie_IPv6_Insert(b *testing.B) {
 	datasets := []struct {
@@ -252,7 +464,7 @@ func BenchmarkTrie_IPv6_Insert(b *testing.B) {
 			for range b.N {
 				trie := New()
 				for _, ip := range ips {
-					_ = trie.Insert(ip)
+					_, _ = trie.Insert(ip)
 				}
 			}
 
@@ -280,7 +492,7 @@ func BenchmarkTrie_IPv6_Search(b *testing.B) {
 
 			trie := New()
 			for _, ip := range ips {
-				_ = trie.Insert(ip)
+				_, _ = trie.Insert(ip)
 			}
 
 			b.ResetTimer()
@@ -325,11 +537,12 @@ func BenchmarkTrie_Japanese_Memory(b *testing.B) {
 
 				trie := New()
 				for _, word := range words {
-					_ = trie.Insert(word)
+					_, _ = trie.Insert(word)
 				}
 
 				runtime.GC()
 				runtime.ReadMemStats(&m2)
+				runtime.KeepAlive(trie)
 
 				// メモリ使用量を記録
 				memUsed := m2.Alloc - m1.Alloc
@@ -340,6 +553,77 @@ func BenchmarkTrie_Japanese_Memory(b *testing.B) {
 	}
 }
 
+// BenchmarkTrie_Japanese_DoubleArrayMemory 日本語辞書データでのダブル配列のメモリ使用量（Trieとの比較はBenchmarkTrie_Japanese_Memory）
+func BenchmarkTrie_Japanese_DoubleArrayMemory(b *testing.B) {
+	datasets := []struct {
+		name string
+		file string
+	}{
+		{"Small", "testdata/japanese/small.txt"},
+		{"Core", "testdata/japanese/core.txt"},
+		{"NotCore", "testdata/japanese/notcore.txt"},
+		{"Full", "testdata/japanese/full.txt"},
+	}
+
+	for _, dataset := range datasets {
+		b.Run(dataset.name, func(b *testing.B) {
+			words, err := loadWordsFromFile(dataset.file)
+			if err != nil {
+				b.Skipf("テストデータが見つかりません: %s (make setup_benchmarkを実行してください)", dataset.file)
+			}
+
+			for range b.N {
+				measureHeap(b, len(words), "bytes/word", func() *DoubleArray {
+					return loadDoubleArrayFromFile(b, dataset.file)
+				})
+			}
+
+			b.ReportMetric(float64(len(words)), "words")
+		})
+	}
+}
+
+// BenchmarkTrie_Japanese_LOUDSMemory 日本語辞書データでのLOUDSトライのメモリ使用量（TrieはBenchmarkTrie_Japanese_Memory、
+// ダブル配列はBenchmarkTrie_Japanese_DoubleArrayMemoryと比較）
+func BenchmarkTrie_Japanese_LOUDSMemory(b *testing.B) {
+	datasets := []struct {
+		name string
+		file string
+	}{
+		{"Small", "testdata/japanese/small.txt"},
+		{"Core", "testdata/japanese/core.txt"},
+		{"NotCore", "testdata/japanese/notcore.txt"},
+		{"Full", "testdata/japanese/full.txt"},
+	}
+
+	for _, dataset := range datasets {
+		b.Run(dataset.name, func(b *testing.B) {
+			words, err := loadWordsFromFile(dataset.file)
+			if err != nil {
+				b.Skipf("テストデータが見つかりません: %s (make setup_benchmarkを実行してください)", dataset.file)
+			}
+
+			trie := New()
+			for _, word := range words {
+				_, _ = trie.Insert(word)
+			}
+
+			for range b.N {
+				measureHeap(b, len(words), "bytes/word", func() *LOUDSTrie {
+					louds, err := trie.ToLOUDS()
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					return louds
+				})
+			}
+
+			b.ReportMetric(float64(len(words)), "words")
+		})
+	}
+}
+
 // BenchmarkTrie_IPv4_Memory IPv4アドレスでのメモリ使用量測定
 func BenchmarkTrie_IPv4_Memory(b *testing.B) {
 	datasets := []struct {
@@ -368,11 +652,12 @@ func BenchmarkTrie_IPv4_Memory(b *testing.B) {
 
 				trie := New()
 				for _, ip := range ips {
-					_ = trie.Insert(ip)
+					_, _ = trie.Insert(ip)
 				}
 
 				runtime.GC()
 				runtime.ReadMemStats(&m2)
+				runtime.KeepAlive(trie)
 
 				// メモリ使用量を記録
 				memUsed := m2.Alloc - m1.Alloc
@@ -411,11 +696,12 @@ func BenchmarkTrie_IPv6_Memory(b *testing.B) {
 
 				trie := New()
 				for _, ip := range ips {
-					_ = trie.Insert(ip)
+					_, _ = trie.Insert(ip)
 				}
 
 				runtime.GC()
 				runtime.ReadMemStats(&m2)
+				runtime.KeepAlive(trie)
 
 				// メモリ使用量を記録
 				memUsed := m2.Alloc - m1.Alloc
@@ -455,7 +741,7 @@ func BenchmarkTrie_Large_Japanese_Specialized(b *testing.B) {
 				for range b.N {
 					trie := New()
 					for _, word := range words {
-						_ = trie.Insert(word)
+						_, _ = trie.Insert(word)
 					}
 				}
 
@@ -465,7 +751,7 @@ func BenchmarkTrie_Large_Japanese_Specialized(b *testing.B) {
 			b.Run("Search", func(b *testing.B) {
 				trie := New()
 				for _, word := range words {
-					_ = trie.Insert(word)
+					_, _ = trie.Insert(word)
 				}
 
 				b.ResetTimer()
@@ -480,7 +766,7 @@ func BenchmarkTrie_Large_Japanese_Specialized(b *testing.B) {
 			b.Run("PrefixSearch", func(b *testing.B) {
 				trie := New()
 				for _, word := range words {
-					_ = trie.Insert(word)
+					_, _ = trie.Insert(word)
 				}
 
 				// 2文字のプレフィックスを生成
@@ -514,7 +800,7 @@ func BenchmarkTrie_Mixed_Workload(b *testing.B) {
 
 	trie := New()
 	for _, data := range mixedData {
-		_ = trie.Insert(data)
+		_, _ = trie.Insert(data)
 	}
 
 	b.ResetTimer()
@@ -540,18 +826,7 @@ func BenchmarkTrie_Mixed_Workload(b *testing.B) {
 
 // loadWordsFromFile ファイルから単語リストを読み込み
 func loadWordsFromFile(filename string) ([]string, error) {
-	// プロジェクトルートからの相対パスを解決
-	if !filepath.IsAbs(filename) {
-		wd, err := os.Getwd()
-		if err != nil {
-			return nil, err
-		}
-		// pkg/patriciatrieから2つ上がプロジェクトルート
-		projectRoot := filepath.Join(wd, "..", "..")
-		filename = filepath.Join(projectRoot, filename)
-	}
-
-	file, err := os.Open(filename)
+	file, err := openTestdata(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -571,6 +846,21 @@ func loadWordsFromFile(filename string) ([]string, error) {
 	return words, scanner.Err()
 }
 
+// openTestdata プロジェクトルートからの相対パスで指定されたテストデータを開く
+func openTestdata(filename string) (*os.File, error) {
+	if !filepath.IsAbs(filename) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		// pkg/patriciatrieから2つ上がプロジェクトルート
+		projectRoot := filepath.Join(wd, "..", "..")
+		filename = filepath.Join(projectRoot, filename)
+	}
+
+	return os.Open(filename)
+}
+
 // generateJapanesePrefixes 日本語単語からプレフィックスを生成
 func generateJapanesePrefixes(words []string, prefixLen, count int) []string {
 	prefixes := make([]string, 0, count)
@@ -0,0 +1,504 @@
+package patriciatrie
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"reflect"
+)
+
+// シリアライズ形式（リトルエンディアン）
+//
+//	ヘッダ:   マジック"PTRI"(4) | バージョン(uint16) | フラグ(uint16) | ノード数(uint64) | キー数(uint64)
+//	ノード:   根から前順（子は先頭バイトの昇順）に並べる
+//	          ラベル長(uvarint) | ラベル | 終端フラグ(1) | 子ノード数(uvarint) | [値の長さ(uvarint) | 値]
+//	トレーラ: ヘッダとノードのCRC-32C(uint32)
+const (
+	serialMagic   = "PTRI"
+	serialVersion = 1
+
+	// serialFlagValues 終端ノードに値が格納されている
+	serialFlagValues = 1 << 0
+
+	// serialNodeTerminal ノードがキーの終端
+	serialNodeTerminal = 1 << 0
+
+	// serialMaxLength ラベル・値・子ノード数として受け付ける最大値（破損データによる巨大な確保を防ぐ）
+	serialMaxLength = 1 << 30
+)
+
+var (
+	// ErrInvalidFormat シリアライズされたデータが壊れているか、トライの形式でない
+	ErrInvalidFormat = errors.New("patriciatrie: invalid serialized data")
+
+	// ErrUnsupportedVersion シリアライズ形式のバージョンに対応していない
+	ErrUnsupportedVersion = errors.New("patriciatrie: unsupported format version")
+
+	// ErrNoValueCodec 値を含むデータの読み込みに値のコーデックが設定されていない
+	ErrNoValueCodec = errors.New("patriciatrie: value codec is not set")
+)
+
+// castagnoli CRC-32Cのテーブル
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// ValueCodec WriteTo/ReadFromで値をバイト列と相互変換するコーデック
+type ValueCodec[V any] interface {
+	// AppendValue 値を符号化してdstに追加
+	AppendValue(dst []byte, value V) ([]byte, error)
+
+	// DecodeValue AppendValueで符号化したバイト列から値を復元
+	DecodeValue(data []byte) (V, error)
+}
+
+// GobCodec encoding/gobで値を符号化するコーデック（値ごとに型情報を含むため汎用だが大きい）
+// ゼロ値（nilのポインタやインターフェースを含む）はgobに渡さず、先頭のフラグのみで表す
+// Vがインターフェース型の場合、格納する具体型は組み込み型以外gob.Registerで登録しておく必要がある
+type GobCodec[V any] struct{}
+
+const (
+	// gobZeroValue 値がゼロ値で、後ろに符号化された値が続かない
+	gobZeroValue = 0
+
+	// gobEncodedValue 後ろにgobで符号化した値が続く
+	gobEncodedValue = 1
+)
+
+// AppendValue 値をgobで符号化してdstに追加
+func (GobCodec[V]) AppendValue(dst []byte, value V) ([]byte, error) {
+	// nilのポインタやインターフェースはgobで符号化できないため、ゼロ値はフラグで表す
+	if reflect.ValueOf(&value).Elem().IsZero() {
+		return append(dst, gobZeroValue), nil
+	}
+
+	buf := bytes.NewBuffer(append(dst, gobEncodedValue))
+
+	// ポインタ経由で渡し、Vがインターフェース型の場合も具体型の情報を含めて符号化する
+	err := gob.NewEncoder(buf).Encode(&value)
+	if err != nil {
+		return dst, fmt.Errorf("gob encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeValue gobで符号化したバイト列から値を復元
+func (GobCodec[V]) DecodeValue(data []byte) (V, error) {
+	var value V
+
+	if len(data) == 0 {
+		return value, fmt.Errorf("%w: empty gob value", ErrInvalidFormat)
+	}
+
+	switch data[0] {
+	case gobZeroValue:
+		if len(data) != 1 {
+			return value, fmt.Errorf("%w: trailing data after zero gob value", ErrInvalidFormat)
+		}
+
+		return value, nil
+	case gobEncodedValue:
+		err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&value)
+		if err != nil {
+			return value, fmt.Errorf("gob decode: %w", err)
+		}
+
+		return value, nil
+	default:
+		return value, fmt.Errorf("%w: unknown gob value flag %d", ErrInvalidFormat, data[0])
+	}
+}
+
+// StringCodec 文字列の値をそのままのバイト列で格納するコーデック
+type StringCodec struct{}
+
+// AppendValue 文字列をdstに追加
+func (StringCodec) AppendValue(dst []byte, value string) ([]byte, error) {
+	return append(dst, value...), nil
+}
+
+// DecodeValue バイト列を文字列に戻す
+func (StringCodec) DecodeValue(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// SetValueCodec WriteTo/ReadFromで値の符号化に使うコーデックを設定（未設定の場合、WriteToはキーのみを書き出す）
+func (t *Trie[V]) SetValueCodec(codec ValueCodec[V]) {
+	t.codec = codec
+}
+
+// WriteTo トライのノード構造をそのままバイナリ形式で書き出し、書き出したバイト数を返す
+func (t *Trie[V]) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	checksum := crc32.New(castagnoli)
+	bw := bufio.NewWriter(io.MultiWriter(counter, checksum))
+
+	var flags uint16
+	if t.codec != nil {
+		flags |= serialFlagValues
+	}
+
+	header := make([]byte, 0, len(serialMagic)+2+2+8+8)
+	header = append(header, serialMagic...)
+	header = binary.LittleEndian.AppendUint16(header, serialVersion)
+	header = binary.LittleEndian.AppendUint16(header, flags)
+	header = binary.LittleEndian.AppendUint64(header, uint64(t.countNodes())) //nolint:gosec // ノード数は非負
+	header = binary.LittleEndian.AppendUint64(header, uint64(t.root.count))   //nolint:gosec // キー数は非負
+
+	_, err := bw.Write(header)
+	if err != nil {
+		return counter.n, fmt.Errorf("write header: %w", err)
+	}
+
+	var buf []byte
+
+	t.traverse(t.root, "", Ascending, func(node *Node[V], _ []byte, _ int) bool {
+		buf = binary.AppendUvarint(buf[:0], uint64(len(node.label)))
+		buf = append(buf, node.label...)
+
+		var nodeFlags byte
+		if node.isEndOfKey {
+			nodeFlags |= serialNodeTerminal
+		}
+
+		buf = append(buf, nodeFlags)
+		buf = binary.AppendUvarint(buf, uint64(node.ChildrenCount())) //nolint:gosec // 子ノード数は非負
+
+		if node.isEndOfKey && t.codec != nil {
+			buf, err = t.appendValue(buf, node.value)
+			if err != nil {
+				return false
+			}
+		}
+
+		_, err = bw.Write(buf)
+
+		return err == nil
+	})
+
+	if err != nil {
+		return counter.n, fmt.Errorf("write node: %w", err)
+	}
+
+	err = bw.Flush()
+	if err != nil {
+		return counter.n, fmt.Errorf("write node: %w", err)
+	}
+
+	// トレーラはチェックサムの対象外
+	_, err = counter.Write(binary.LittleEndian.AppendUint32(nil, checksum.Sum32()))
+	if err != nil {
+		return counter.n, fmt.Errorf("write checksum: %w", err)
+	}
+
+	return counter.n, nil
+}
+
+// appendValue 値を「長さ＋符号化したバイト列」の形でbufに追加
+func (t *Trie[V]) appendValue(buf []byte, value V) ([]byte, error) {
+	encoded, err := t.codec.AppendValue(nil, value)
+	if err != nil {
+		return buf, fmt.Errorf("encode value: %w", err)
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(encoded)))
+
+	return append(buf, encoded...), nil
+}
+
+// ReadFrom WriteToで書き出したデータをEOFまで読み込み、トライの内容を置き換えて読み込んだバイト数を返す
+// ノードは書き出した構造のまま復元し、キーの再挿入は行わない（エラーの場合、トライは変更しない）
+func (t *Trie[V]) ReadFrom(r io.Reader) (int64, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(raw)), fmt.Errorf("read: %w", err)
+	}
+
+	n := int64(len(raw))
+
+	const headerLen = len(serialMagic) + 2 + 2 + 8 + 8
+	if len(raw) < headerLen+4 {
+		return n, fmt.Errorf("%w: %d bytes is too short", ErrInvalidFormat, len(raw))
+	}
+
+	if string(raw[:len(serialMagic)]) != serialMagic {
+		return n, fmt.Errorf("%w: bad magic %q", ErrInvalidFormat, raw[:len(serialMagic)])
+	}
+
+	version := binary.LittleEndian.Uint16(raw[4:])
+	if version != serialVersion {
+		return n, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	// ノードを解析する前に全体のチェックサムを確認
+	body, trailer := raw[:len(raw)-4], raw[len(raw)-4:]
+	if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(trailer) {
+		return n, fmt.Errorf("%w: checksum mismatch", ErrInvalidFormat)
+	}
+
+	flags := binary.LittleEndian.Uint16(raw[6:])
+	if flags&serialFlagValues != 0 && t.codec == nil {
+		return n, ErrNoValueCodec
+	}
+
+	nodeCount := binary.LittleEndian.Uint64(raw[8:])
+	keyCount := binary.LittleEndian.Uint64(raw[16:])
+
+	// ラベルはノードごとに確保せず、1つの文字列から切り出す
+	decoder := &serialDecoder{data: string(body), pos: headerLen}
+
+	root, err := t.readNodes(decoder, nodeCount, flags&serialFlagValues != 0)
+	if err != nil {
+		return n, err
+	}
+
+	if decoder.pos != len(decoder.data) {
+		return n, fmt.Errorf("%w: %d bytes of trailing data", ErrInvalidFormat, len(decoder.data)-decoder.pos)
+	}
+
+	if uint64(root.count) != keyCount { //nolint:gosec // キー数は非負
+		return n, fmt.Errorf("%w: %d keys, header says %d", ErrInvalidFormat, root.count, keyCount)
+	}
+
+	t.root = root
+
+	return n, nil
+}
+
+// serialFrame 読み込み中のノードと、まだ読んでいない子ノードの数
+type serialFrame[V any] struct {
+	node       *Node[V]
+	remaining  uint64
+	childStart int
+}
+
+// readNodes 前順に並んだノードを読み込んで根ノードを返す
+// 子ノードは親ごとにまとめてから必要な大きさの索引に設定し、部分木のキー数も同時に数える
+func (t *Trie[V]) readNodes(decoder *serialDecoder, nodeCount uint64, hasValues bool) (*Node[V], error) {
+	var tree serialTree[V]
+
+	for range nodeCount {
+		node, childCount, err := t.readNode(decoder, hasValues)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tree.attach(node)
+		if err != nil {
+			return nil, err
+		}
+
+		tree.stack = append(tree.stack, serialFrame[V]{node: node, remaining: childCount, childStart: len(tree.children)})
+
+		// 子ノードをすべて読み終えたノードを確定
+		for len(tree.stack) > 0 && tree.stack[len(tree.stack)-1].remaining == 0 {
+			err = tree.complete()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if tree.root == nil || len(tree.stack) > 0 {
+		return nil, fmt.Errorf("%w: node count %d does not match the tree", ErrInvalidFormat, nodeCount)
+	}
+
+	return tree.root, nil
+}
+
+// readNode 1つのノードの記録（ラベル、フラグ、子ノードの数、値）を読み込み、ノードと子ノードの数を返す
+func (t *Trie[V]) readNode(decoder *serialDecoder, hasValues bool) (*Node[V], uint64, error) {
+	label, err := decoder.readString()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	node := NewNode[V](label)
+
+	nodeFlags, err := decoder.readByte()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	childCount, err := decoder.readUvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if nodeFlags&serialNodeTerminal == 0 {
+		return node, childCount, nil
+	}
+
+	node.isEndOfKey = true
+	node.count = 1
+
+	if hasValues {
+		encoded, err := decoder.readString()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		node.value, err = t.codec.DecodeValue([]byte(encoded))
+		if err != nil {
+			return nil, 0, fmt.Errorf("decode value: %w", err)
+		}
+	}
+
+	return node, childCount, nil
+}
+
+// serialTree 読み込み中の木（確定していないノードのスタックと、親ごとにまとめている途中の子ノード）
+type serialTree[V any] struct {
+	stack    []serialFrame[V]
+	children []*Node[V]
+	root     *Node[V]
+}
+
+// attach 読み込んだノードを根、またはスタックの末尾のノードの子として追加
+func (tree *serialTree[V]) attach(node *Node[V]) error {
+	if tree.root == nil {
+		if node.label != "" {
+			return fmt.Errorf("%w: root has label %q", ErrInvalidFormat, node.label)
+		}
+
+		tree.root = node
+
+		return nil
+	}
+
+	if len(tree.stack) == 0 {
+		return fmt.Errorf("%w: more nodes than the tree holds", ErrInvalidFormat)
+	}
+
+	if node.label == "" {
+		return fmt.Errorf("%w: node has empty label", ErrInvalidFormat)
+	}
+
+	// 兄弟ノードは先頭バイトの昇順
+	parent := &tree.stack[len(tree.stack)-1]
+	if len(tree.children) > parent.childStart && tree.children[len(tree.children)-1].label[0] >= node.label[0] {
+		return fmt.Errorf("%w: children out of order at %q", ErrInvalidFormat, node.label)
+	}
+
+	tree.children = append(tree.children, node)
+	parent.remaining--
+
+	return nil
+}
+
+// complete 子ノードをすべて読み終えたスタックの末尾のノードを確定し、部分木のキー数と子ノードを設定
+func (tree *serialTree[V]) complete() error {
+	last := tree.stack[len(tree.stack)-1]
+	tree.stack = tree.stack[:len(tree.stack)-1]
+
+	children := tree.children[last.childStart:]
+
+	// 根以外の非終端ノードは子ノードを2つ以上持つ
+	if len(tree.stack) > 0 && !last.node.isEndOfKey && len(children) < 2 {
+		return fmt.Errorf("%w: non-terminal node %q has %d children", ErrInvalidFormat, last.node.label, len(children))
+	}
+
+	for _, child := range children {
+		last.node.count += child.count
+	}
+
+	last.node.setChildren(children)
+	tree.children = tree.children[:last.childStart]
+
+	return nil
+}
+
+// countNodes トライのノード数（根を含む）を数える
+func (t *Trie[V]) countNodes() int {
+	count := 0
+
+	t.traverse(t.root, "", Ascending, func(*Node[V], []byte, int) bool {
+		count++
+
+		return true
+	})
+
+	return count
+}
+
+// countingWriter 書き出したバイト数を数えるio.Writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write wに書き出してバイト数を加算
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// serialDecoder シリアライズされたデータを先頭から順に読む
+type serialDecoder struct {
+	data string
+	pos  int
+}
+
+// readByte 1バイト読み込む
+func (d *serialDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+	}
+
+	b := d.data[d.pos]
+	d.pos++
+
+	return b, nil
+}
+
+// readUvarint 可変長の符号なし整数を読み込み、上限を超える値を拒否
+func (d *serialDecoder) readUvarint() (uint64, error) {
+	var v uint64
+
+	for shift := 0; ; shift += 7 {
+		b, err := d.readByte()
+		if err != nil {
+			return 0, err
+		}
+
+		if shift >= 64 {
+			return 0, fmt.Errorf("%w: varint overflows", ErrInvalidFormat)
+		}
+
+		v |= uint64(b&0x7f) << shift
+
+		if b < 0x80 {
+			break
+		}
+	}
+
+	if v > serialMaxLength {
+		return 0, fmt.Errorf("%w: length %d too large", ErrInvalidFormat, v)
+	}
+
+	return v, nil
+}
+
+// readString 長さ付きのバイト列を読み込み、データの部分文字列として返す
+func (d *serialDecoder) readString() (string, error) {
+	length, err := d.readUvarint()
+	if err != nil {
+		return "", err
+	}
+
+	if length > uint64(len(d.data)-d.pos) {
+		return "", fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+	}
+
+	end := d.pos + int(length) //nolint:gosec // 残りのデータ長以下
+	str := d.data[d.pos:end]
+	d.pos = end
+
+	return str, nil
+}
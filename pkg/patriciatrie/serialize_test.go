@@ -0,0 +1,296 @@
+package patriciatrie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_WriteToReadFrom(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{"空のトライ", nil},
+		{"空文字列のキー", []string{"", "a"}},
+		{"分岐と連鎖", []string{"cat", "cats", "car", "dog", "do", "d"}},
+		{"多分岐", slices.Collect(func(yield func(string) bool) {
+			for i := range childTableSize {
+				if !yield(string([]byte{'x', byte(i)})) {
+					return
+				}
+			}
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trie := New()
+			for _, key := range tt.keys {
+				_, err := trie.Insert(key)
+				require.NoError(t, err)
+			}
+
+			var buf bytes.Buffer
+
+			written, err := trie.WriteTo(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, int64(buf.Len()), written)
+
+			loaded := New()
+
+			read, err := loaded.ReadFrom(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			assert.Equal(t, written, read)
+
+			require.NoError(t, loaded.Validate())
+			assert.Equal(t, slices.Collect(trie.Keys()), slices.Collect(loaded.Keys()))
+			assert.Equal(t, trie.Stats().Nodes, loaded.Stats().Nodes)
+		})
+	}
+}
+
+func TestTrie_WriteToReadFromValues(t *testing.T) {
+	t.Parallel()
+
+	trie := NewTrie[string]()
+	trie.SetValueCodec(StringCodec{})
+
+	for _, key := range []string{"東京", "東京都", "京都", ""} {
+		_, err := trie.Put(key, "value:"+key)
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+
+	_, err := trie.WriteTo(&buf)
+	require.NoError(t, err)
+
+	// 値を含むデータはコーデックなしでは読み込めない
+	_, err = NewTrie[string]().ReadFrom(bytes.NewReader(buf.Bytes()))
+	require.ErrorIs(t, err, ErrNoValueCodec)
+
+	loaded := NewTrie[string]()
+	loaded.SetValueCodec(StringCodec{})
+
+	_, err = loaded.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	for key, value := range trie.All() {
+		got, ok := loaded.Get(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, value, got)
+	}
+
+	assert.Equal(t, 4, loaded.Len())
+}
+
+func TestTrie_WriteToReadFromGob(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		ID    int
+		Tags  []string
+		Score float64
+	}
+
+	trie := NewTrie[entry]()
+	trie.SetValueCodec(GobCodec[entry]{})
+
+	_, err := trie.Put("alpha", entry{ID: 1, Tags: []string{"a"}, Score: 0.5})
+	require.NoError(t, err)
+	_, err = trie.Put("alphabet", entry{ID: 2})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	_, err = trie.WriteTo(&buf)
+	require.NoError(t, err)
+
+	loaded := NewTrie[entry]()
+	loaded.SetValueCodec(GobCodec[entry]{})
+
+	_, err = loaded.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	value, ok := loaded.Get("alpha")
+	require.True(t, ok)
+	assert.Equal(t, entry{ID: 1, Tags: []string{"a"}, Score: 0.5}, value)
+}
+
+func TestTrie_WriteToReadFromGobInterface(t *testing.T) {
+	t.Parallel()
+
+	// New()で作るキー集合のトライは値がnilのキーと具体型の値を持つキーが混在する
+	trie := New()
+	trie.SetValueCodec(GobCodec[any]{})
+
+	_, err := trie.Insert("nil")
+	require.NoError(t, err)
+
+	values := map[string]any{"int": 42, "zero": 0, "string": "東京", "slice": []string{"a", "b"}}
+	for key, value := range values {
+		_, err = trie.Put(key, value)
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+
+	_, err = trie.WriteTo(&buf)
+	require.NoError(t, err)
+
+	loaded := New()
+	loaded.SetValueCodec(GobCodec[any]{})
+
+	_, err = loaded.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	value, ok := loaded.Get("nil")
+	assert.True(t, ok)
+	assert.Nil(t, value)
+
+	for key, want := range values {
+		got, ok := loaded.Get(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+}
+
+func TestTrie_WriteToReadFromGobPointer(t *testing.T) {
+	t.Parallel()
+
+	// Insertのみで追加したキーの値はnilポインタ
+	trie := NewTrie[*int]()
+	trie.SetValueCodec(GobCodec[*int]{})
+
+	for _, key := range []string{"a", "ab", "b"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	seven := 7
+	_, err := trie.Put("seven", &seven)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	_, err = trie.WriteTo(&buf)
+	require.NoError(t, err)
+
+	loaded := NewTrie[*int]()
+	loaded.SetValueCodec(GobCodec[*int]{})
+
+	_, err = loaded.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "ab", "b", "seven"}, slices.Collect(loaded.Keys()))
+
+	value, ok := loaded.Get("ab")
+	assert.True(t, ok)
+	assert.Nil(t, value)
+
+	value, ok = loaded.Get("seven")
+	require.True(t, ok)
+	require.NotNil(t, value)
+	assert.Equal(t, 7, *value)
+}
+
+func TestGobCodec_RejectsUnknownFlag(t *testing.T) {
+	t.Parallel()
+
+	_, err := GobCodec[int]{}.DecodeValue([]byte{2})
+	require.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = GobCodec[int]{}.DecodeValue(nil)
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestTrie_ReadFromRejectsCorruptData(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"cat", "cats", "car", "dog"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+
+	_, err := trie.WriteTo(&buf)
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+		target  error
+	}{
+		{"マジックの不一致", func(data []byte) []byte {
+			data[0] = 'X'
+
+			return data
+		}, ErrInvalidFormat},
+		{"未対応のバージョン", func(data []byte) []byte {
+			binary.LittleEndian.PutUint16(data[4:], serialVersion+1)
+
+			return data
+		}, ErrUnsupportedVersion},
+		{"ラベルの破損", func(data []byte) []byte {
+			data[len(data)-8] ^= 0xff
+
+			return data
+		}, ErrInvalidFormat},
+		{"途中で切れたデータ", func(data []byte) []byte {
+			return data[:len(data)-10]
+		}, ErrInvalidFormat},
+		{"チェックサムの破損", func(data []byte) []byte {
+			data[len(data)-1] ^= 0xff
+
+			return data
+		}, ErrInvalidFormat},
+		{"ノード数の不一致（チェックサムは正しい）", func(data []byte) []byte {
+			binary.LittleEndian.PutUint64(data[8:], binary.LittleEndian.Uint64(data[8:])-1)
+
+			return resum(data)
+		}, ErrInvalidFormat},
+		{"末尾の余分なデータ（チェックサムは正しい）", func(data []byte) []byte {
+			data = slices.Insert(data, len(data)-4, 0)
+
+			return resum(data)
+		}, ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			loaded := New()
+			_, err := loaded.Insert("existing")
+			require.NoError(t, err)
+
+			_, err = loaded.ReadFrom(bytes.NewReader(tt.corrupt(slices.Clone(data))))
+			require.ErrorIs(t, err, tt.target)
+
+			// 読み込みに失敗した場合は元の内容を保つ
+			assert.Equal(t, []string{"existing"}, slices.Collect(loaded.Keys()))
+		})
+	}
+}
+
+// resum 書き換えたデータのチェックサムを計算し直す
+func resum(data []byte) []byte {
+	body := data[:len(data)-4]
+	binary.LittleEndian.PutUint32(data[len(data)-4:], crc32.Checksum(body, castagnoli))
+
+	return data
+}
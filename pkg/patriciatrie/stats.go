@@ -0,0 +1,132 @@
+package patriciatrie
+
+import "reflect"
+
+// 子ノードの索引のメモリ見積もりに使うポインタのバイト数（64bit環境）
+const pointerBytes = 8
+
+// Stats トライの構造統計
+type Stats struct {
+	// ノードの総数（根を含む）
+	Nodes int
+
+	// 終端ノードの数（格納されているキーの数）
+	TerminalNodes int
+
+	// 根から最も深いノードまでのエッジ数
+	MaxDepth int
+
+	// 根から終端ノードまでのエッジ数の平均
+	AvgDepth float64
+
+	// エッジラベルの長さごとのノード数（根を除く）
+	LabelLengths map[int]int
+
+	// 子ノードの数ごとのノード数
+	Branching map[int]int
+
+	// エッジラベルの総バイト数
+	LabelBytes int
+
+	// ノード構造体の総バイト数
+	NodeBytes int
+
+	// 子ノードの索引（先頭バイトの配列、子ノードの配列、密なノードのテーブル）の推定バイト数
+	// ノード構造体に含まれるスライスヘッダは含まない
+	ChildIndexBytes int
+}
+
+// QueryStats 1回の検索で辿ったノードの統計
+type QueryStats struct {
+	// 訪問したノードの数（根を含む）
+	NodesVisited int
+
+	// 到達した最大の深さ（根からのエッジ数）
+	MaxDepth int
+}
+
+// Stats トライ全体の構造統計を取得
+func (t *Trie[V]) Stats() Stats {
+	stats := Stats{
+		LabelLengths: make(map[int]int),
+		Branching:    make(map[int]int),
+	}
+
+	depthSum := 0
+	t.collectStats(t.root, 0, &stats, &depthSum)
+
+	if stats.TerminalNodes > 0 {
+		stats.AvgDepth = float64(depthSum) / float64(stats.TerminalNodes)
+	}
+
+	stats.NodeBytes = stats.Nodes * int(reflect.TypeFor[Node[V]]().Size())
+
+	return stats
+}
+
+// collectStats 指定されたノード以下の構造統計を集計
+func (t *Trie[V]) collectStats(node *Node[V], depth int, stats *Stats, depthSum *int) {
+	stats.Nodes++
+	stats.MaxDepth = max(stats.MaxDepth, depth)
+	stats.Branching[node.ChildrenCount()]++
+	stats.LabelBytes += len(node.label)
+	stats.ChildIndexBytes += node.childIndexBytes()
+
+	if depth > 0 {
+		stats.LabelLengths[len(node.label)]++
+	}
+
+	if node.isEndOfKey {
+		stats.TerminalNodes++
+		*depthSum += depth
+	}
+
+	for i := range node.ChildrenCount() {
+		t.collectStats(node.childAt(i), depth+1, stats, depthSum)
+	}
+}
+
+// childIndexBytes 子ノードの索引が確保しているバイト数を推定
+func (n *Node[V]) childIndexBytes() int {
+	size := cap(n.childKeys) + cap(n.childNodes)*pointerBytes
+	if n.childSlots != nil {
+		size += childTableSize
+	}
+
+	return size
+}
+
+// FindByPrefixWithStats FindByPrefixと同じ検索を行い、実際に辿ったノードの統計も返す
+func (t *Trie[V]) FindByPrefixWithStats(prefix string) ([]string, QueryStats) {
+	var result []string
+
+	node, nodeKey, depth := t.findPrefixNode(prefix)
+
+	// プレフィックスの降下で訪問したノード（根を含む）
+	stats := QueryStats{NodesVisited: depth + 1, MaxDepth: depth}
+	if node == nil {
+		return result, stats
+	}
+
+	t.collectWithStats(node, nodeKey, depth, &stats, &result)
+
+	return result, stats
+}
+
+// collectWithStats 指定されたノード以下のキーを昇順で収集し、訪問した子孫ノードを記録
+func (t *Trie[V]) collectWithStats(node *Node[V], currentKey string, depth int, stats *QueryStats, result *[]string) {
+	t.traverse(node, currentKey, Ascending, func(n *Node[V], key []byte, d int) bool {
+		// 開始ノードはプレフィックスの降下で記録済み
+		if d > 0 {
+			stats.NodesVisited++
+		}
+
+		stats.MaxDepth = max(stats.MaxDepth, depth+d)
+
+		if n.isEndOfKey {
+			*result = append(*result, string(key))
+		}
+
+		return true
+	})
+}
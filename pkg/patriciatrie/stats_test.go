@@ -0,0 +1,79 @@
+package patriciatrie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_Stats(t *testing.T) {
+	t.Parallel()
+
+	// root ─ "ca" ─┬─ "t"(終端) ─ "s"(終端)
+	//              └─ "r"(終端)
+	//      └ "dog"(終端)
+	trie := New()
+	for _, key := range []string{"cat", "cats", "car", "dog"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	stats := trie.Stats()
+
+	assert.Equal(t, 6, stats.Nodes)
+	assert.Equal(t, 4, stats.TerminalNodes)
+	assert.Equal(t, 3, stats.MaxDepth)
+	assert.InDelta(t, float64(2+3+2+1)/4, stats.AvgDepth, 1e-9)
+	assert.Equal(t, map[int]int{1: 3, 2: 1, 3: 1}, stats.LabelLengths)
+	assert.Equal(t, map[int]int{0: 3, 1: 1, 2: 2}, stats.Branching)
+	assert.Equal(t, len("ca")+len("t")+len("s")+len("r")+len("dog"), stats.LabelBytes)
+	assert.Positive(t, stats.NodeBytes)
+	assert.Positive(t, stats.ChildIndexBytes)
+}
+
+func TestTrie_StatsEmpty(t *testing.T) {
+	t.Parallel()
+
+	stats := New().Stats()
+
+	assert.Equal(t, 1, stats.Nodes)
+	assert.Equal(t, 0, stats.TerminalNodes)
+	assert.Equal(t, 0, stats.MaxDepth)
+	assert.InDelta(t, 0.0, stats.AvgDepth, 1e-9)
+}
+
+func TestTrie_FindByPrefixWithStats(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"cat", "cats", "car", "dog"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		expected []string
+		stats    QueryStats
+	}{
+		{"部分木全体", "ca", []string{"car", "cat", "cats"}, QueryStats{NodesVisited: 5, MaxDepth: 3}},
+		{"ラベル途中で終わるプレフィックス", "do", []string{"dog"}, QueryStats{NodesVisited: 2, MaxDepth: 1}},
+		{"葉まで降下", "cats", []string{"cats"}, QueryStats{NodesVisited: 4, MaxDepth: 3}},
+		{"マッチしないプレフィックス", "cb", nil, QueryStats{NodesVisited: 2, MaxDepth: 1}},
+		{"子ノードなし", "x", nil, QueryStats{NodesVisited: 1, MaxDepth: 0}},
+		{"全体", "", []string{"car", "cat", "cats", "dog"}, QueryStats{NodesVisited: 6, MaxDepth: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			results, stats := trie.FindByPrefixWithStats(tt.prefix)
+			assert.Equal(t, tt.expected, results)
+			assert.Equal(t, tt.stats, stats)
+			assert.Equal(t, trie.FindByPrefix(tt.prefix), results)
+		})
+	}
+}
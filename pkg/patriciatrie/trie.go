@@ -1,107 +1,239 @@
 // Package patriciatrie パトリシアトライの実装を提供
 package patriciatrie
 
-// Trie パトリシアトライの構造体
-type Trie struct {
-	root *Node
+import "errors"
+
+// ErrKeyNotFound 指定されたキーがトライに存在しない
+var ErrKeyNotFound = errors.New("patriciatrie: key not found")
+
+// Trie パトリシアトライの構造体（Vはキーに関連付ける値の型）
+type Trie[V any] struct {
+	root *Node[V]
+
+	// WriteTo/ReadFromで値の符号化に使うコーデック（nilの場合は値を保存しない）
+	codec ValueCodec[V]
 }
 
-// New 新しいパトリシアトライを作成
-func New() *Trie {
-	return &Trie{
-		root: &Node{
-			label:      "",
-			isEndOfKey: false,
-			children:   make(map[byte]*Node),
-			value:      nil,
-		},
+// New 値の型を指定しない新しいパトリシアトライを作成（キー集合として使う場合向け）
+func New() *Trie[any] {
+	return NewTrie[any]()
+}
+
+// NewTrie 値の型Vを持つ新しいパトリシアトライを作成
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{
+		root: NewNode[V](""),
 	}
 }
 
-// Insert キーをトライに挿入
-func (t *Trie) Insert(key string) error {
-	if key == "" {
-		t.root.isEndOfKey = true
+// Insert キーをトライに挿入し、新規キーだったかを返す（既存キーの値は保持）
+func (t *Trie[V]) Insert(key string) (bool, error) {
+	_, added, err := t.insertNode(key)
 
-		return nil
+	return added, err
+}
+
+// Put キーと値をトライに格納し、新規キーだったかを返す（既存キーの値は上書き）
+func (t *Trie[V]) Put(key string, value V) (bool, error) {
+	node, added, err := t.insertNode(key)
+	if err != nil {
+		return false, err
 	}
 
-	return t.insertNode(t.root, key)
+	node.value = value
+
+	return added, nil
 }
 
-// Search キーがトライに存在するかを検索
-func (t *Trie) Search(key string) bool {
-	if key == "" {
-		return t.root.isEndOfKey
+// Get キーに対応する値を取得
+func (t *Trie[V]) Get(key string) (V, bool) {
+	node := t.searchNode(key)
+	if node == nil || !node.isEndOfKey {
+		var zero V
+
+		return zero, false
 	}
 
-	return t.searchNode(t.root, key)
+	return node.value, true
 }
 
-// Delete キーをトライから削除
-func (t *Trie) Delete(key string) error {
-	if key == "" {
-		t.root.isEndOfKey = false
+// Search キーがトライに存在するかを検索
+func (t *Trie[V]) Search(key string) bool {
+	node := t.searchNode(key)
 
-		return nil
+	return node != nil && node.isEndOfKey
+}
+
+// Delete キーをトライから削除し、削除前の値を返す（キーが存在しない場合はErrKeyNotFound）
+func (t *Trie[V]) Delete(key string) (V, error) {
+	old, removed, err := t.deleteNode(key)
+	if err != nil {
+		return old, err
+	}
+
+	if !removed {
+		return old, ErrKeyNotFound
 	}
 
-	return t.deleteNode(t.root, key)
+	return old, nil
 }
 
-// FindByPrefix 指定されたプレフィックスを持つすべてのキーを検索
-func (t *Trie) FindByPrefix(prefix string) []string {
-	var result []string
-	t.findKeysWithPrefix(t.root, "", prefix, &result)
+// DeletePrefix 指定されたプレフィックスを持つすべてのキーを部分木ごと削除し、削除したキーの数を返す
+func (t *Trie[V]) DeletePrefix(prefix string) int {
+	if prefix == "" {
+		removed := t.root.count
+		t.root = NewNode[V]("")
 
-	return result
+		return removed
+	}
+
+	// プレフィックスの終端を含むエッジまで降下し、経路を記録
+	path := []*Node[V]{t.root}
+	consumed := 0
+
+	for {
+		remaining := prefix[consumed:]
+		parent := path[len(path)-1]
+
+		child, exists := parent.GetChild(remaining[0])
+		if !exists {
+			return 0
+		}
+
+		commonLen := t.findCommonPrefixLength(child.label, remaining)
+
+		if commonLen == len(remaining) {
+			// 子ノードの部分木がプレフィックスを持つキーの集合
+			return t.detachSubtree(path, child)
+		}
+
+		if commonLen < len(child.label) {
+			return 0
+		}
+
+		consumed += commonLen
+		path = append(path, child)
+	}
 }
 
-// insertNode 指定されたノードから始まってキーを挿入
-func (t *Trie) insertNode(node *Node, key string) error {
-	if len(key) == 0 {
-		node.isEndOfKey = true
+// detachSubtree 経路の末尾のノードから部分木を切り離し、経路上の部分木キー数とパス圧縮を修正
+func (t *Trie[V]) detachSubtree(path []*Node[V], subtree *Node[V]) int {
+	removed := subtree.count
+	path[len(path)-1].RemoveChild(subtree.label[0])
 
-		return nil
+	for _, node := range path {
+		node.count -= removed
+	}
+
+	// 子を失ったノードから根に向かって、不要なノードの削除と再圧縮を行う
+	for i := len(path) - 1; i > 0; i-- {
+		_ = t.cleanupAfterDelete(path[i-1], path[i], path[i].label[0])
 	}
 
-	firstByte := key[0]
+	return removed
+}
 
-	// 子ノードが存在しない場合、新しいノードを作成
-	if !node.HasChild(firstByte) {
-		newNode := NewNode(key)
-		newNode.isEndOfKey = true
-		node.AddChild(firstByte, newNode)
+// Len トライに格納されているキーの数を取得
+func (t *Trie[V]) Len() int {
+	return t.root.count
+}
 
-		return nil
+// CountPrefix 指定されたプレフィックスを持つキーの数をO(プレフィックス長)で取得
+func (t *Trie[V]) CountPrefix(prefix string) int {
+	node, _, _ := t.findPrefixNode(prefix)
+	if node == nil {
+		return 0
 	}
 
-	// 子ノードが存在する場合
-	child, _ := node.GetChild(firstByte)
+	return node.count
+}
 
-	// 共通プレフィックスの長さを計算
-	commonLen := t.findCommonPrefixLength(child.label, key)
+// FindByPrefix 指定されたプレフィックスを持つすべてのキーをバイト順の昇順で検索
+func (t *Trie[V]) FindByPrefix(prefix string) []string {
+	return t.FindByPrefixOrder(prefix, Ascending)
+}
 
-	if commonLen == len(child.label) {
-		// 子ノードのラベルが完全にマッチする場合
-		// 残りのキーで再帰的に挿入
-		remaining := key[commonLen:]
+// FindByPrefixOrder 指定されたプレフィックスを持つすべてのキーを指定された順序で検索
+func (t *Trie[V]) FindByPrefixOrder(prefix string, order Order) []string {
+	var result []string
 
-		return t.insertNode(child, remaining)
+	node, nodeKey, _ := t.findPrefixNode(prefix)
+	if node == nil {
+		return result
 	}
 
-	if commonLen == len(key) {
-		// 挿入するキーが既存ノードのプレフィックスの場合
-		// 既存ノードを分割して新しい中間ノードを作成
-		return t.splitNode(node, child, firstByte, commonLen)
+	// プレフィックスに一致する部分木のみを収集
+	t.walkNode(node, nodeKey, order, func(key string, _ V) bool {
+		result = append(result, key)
+
+		return true
+	})
+
+	return result
+}
+
+// insertNode キーを挿入し、終端ノードと新規キーだったかを返す
+// 新規キーの場合、経路上の各ノードの部分木キー数を1つ増やす
+func (t *Trie[V]) insertNode(key string) (*Node[V], bool, error) {
+	// 既存キーなら経路を変更せずに返す（降下中に部分木キー数を増やすため先に確認する）
+	if node := t.searchNode(key); node != nil && node.isEndOfKey {
+		return node, false, nil
 	}
 
-	// 部分的にマッチする場合、ノードを分割
-	return t.splitNodeWithNewBranch(node, child, firstByte, key, commonLen)
+	node := t.root
+
+	for {
+		// 新規キーであることは確定しているため、経路上のノードのキー数を増やしながら降下
+		node.count++
+
+		if len(key) == 0 {
+			node.isEndOfKey = true
+
+			return node, true, nil
+		}
+
+		firstByte := key[0]
+
+		// 子ノードが存在しない場合、新しいノードを作成
+		child, exists := node.GetChild(firstByte)
+		if !exists {
+			newNode := NewNode[V](key)
+			newNode.isEndOfKey = true
+			newNode.count = 1
+			node.AddChild(firstByte, newNode)
+
+			return newNode, true, nil
+		}
+
+		// 共通プレフィックスの長さを計算
+		commonLen := t.findCommonPrefixLength(child.label, key)
+
+		if commonLen == len(child.label) {
+			// 子ノードのラベルが完全にマッチする場合、残りのキーで降下を続ける
+			key = key[commonLen:]
+			node = child
+
+			continue
+		}
+
+		if commonLen == len(key) {
+			// 挿入するキーが既存ノードのプレフィックスの場合
+			// 既存ノードを分割して新しい中間ノードを作成
+			return t.splitNode(node, child, firstByte, commonLen)
+		}
+
+		// 部分的にマッチする場合、ノードを分割
+		return t.splitNodeWithNewBranch(node, child, firstByte, key, commonLen)
+	}
 }
 
 // findCommonPrefixLength 2つの文字列の共通プレフィックスの長さを計算
-func (t *Trie) findCommonPrefixLength(s1, s2 string) int {
+func (t *Trie[V]) findCommonPrefixLength(s1, s2 string) int {
+	return commonPrefixLength(s1, s2)
+}
+
+// commonPrefixLength 2つの文字列の共通プレフィックスの長さを計算
+func commonPrefixLength(s1, s2 string) int {
 	minLen := len(s1)
 	if len(s2) < minLen {
 		minLen = len(s2)
@@ -117,10 +249,11 @@ func (t *Trie) findCommonPrefixLength(s1, s2 string) int {
 }
 
 // splitNode 既存ノードを分割（挿入キーが既存ラベルのプレフィックス）
-func (t *Trie) splitNode(parent *Node, child *Node, firstByte byte, commonLen int) error {
-	// 新しい中間ノードを作成
-	intermediateNode := NewNode(child.label[:commonLen])
+func (t *Trie[V]) splitNode(parent *Node[V], child *Node[V], firstByte byte, commonLen int) (*Node[V], bool, error) {
+	// 新しい中間ノードを作成（部分木のキー数は既存の子ノード分と自身の1つ）
+	intermediateNode := NewNode[V](child.label[:commonLen])
 	intermediateNode.isEndOfKey = true
+	intermediateNode.count = child.count + 1
 
 	// 既存の子ノードのラベルを短縮
 	remainingLabel := child.label[commonLen:]
@@ -132,47 +265,40 @@ func (t *Trie) splitNode(parent *Node, child *Node, firstByte byte, commonLen in
 	}
 
 	// 親ノードに中間ノードを接続
-	parent.children[firstByte] = intermediateNode
+	parent.AddChild(firstByte, intermediateNode)
 
-	return nil
+	return intermediateNode, true, nil
 }
 
-// searchNode 指定されたノードから始まってキーを検索
-func (t *Trie) searchNode(node *Node, key string) bool {
-	if len(key) == 0 {
-		return node.isEndOfKey
-	}
-
-	firstByte := key[0]
-
-	// 対応する子ノードが存在しない場合、キーは存在しない
-	if !node.HasChild(firstByte) {
-		return false
-	}
+// searchNode キーに完全一致するノードを検索（見つからない場合はnil）
+func (t *Trie[V]) searchNode(key string) *Node[V] {
+	node := t.root
 
-	child, _ := node.GetChild(firstByte)
+	for len(key) > 0 {
+		// 対応する子ノードが存在しない場合、キーは存在しない
+		child, exists := node.GetChild(key[0])
+		if !exists {
+			return nil
+		}
 
-	// 子ノードのラベルと比較
-	if len(key) < len(child.label) {
-		// キーが子ノードのラベルより短い場合、完全一致しない
-		return false
-	}
+		// キーが子ノードのラベルより短いか、ラベルがキーのプレフィックスとして一致しない場合
+		if len(key) < len(child.label) || key[:len(child.label)] != child.label {
+			return nil
+		}
 
-	// ラベルがキーのプレフィックスとして一致しない場合
-	if key[:len(child.label)] != child.label {
-		return false
+		// ラベルが完全に一致する場合、残りのキーで降下を続ける
+		key = key[len(child.label):]
+		node = child
 	}
 
-	// ラベルが完全に一致する場合、残りのキーで再帰的に検索
-	remaining := key[len(child.label):]
-
-	return t.searchNode(child, remaining)
+	return node
 }
 
 // splitNodeWithNewBranch ノードを分割して新しい分岐を作成
-func (t *Trie) splitNodeWithNewBranch(parent *Node, child *Node, firstByte byte, key string, commonLen int) error {
-	// 共通部分で中間ノードを作成
-	intermediateNode := NewNode(key[:commonLen])
+func (t *Trie[V]) splitNodeWithNewBranch(parent *Node[V], child *Node[V], firstByte byte, key string, commonLen int) (*Node[V], bool, error) {
+	// 共通部分で中間ノードを作成（部分木のキー数は既存の子ノード分と新しいキーの1つ）
+	intermediateNode := NewNode[V](key[:commonLen])
+	intermediateNode.count = child.count + 1
 
 	// 既存の子ノードのラベルを更新
 	childRemainingLabel := child.label[commonLen:]
@@ -180,8 +306,9 @@ func (t *Trie) splitNodeWithNewBranch(parent *Node, child *Node, firstByte byte,
 
 	// 新しいノードを作成
 	newRemainingKey := key[commonLen:]
-	newNode := NewNode(newRemainingKey)
+	newNode := NewNode[V](newRemainingKey)
 	newNode.isEndOfKey = true
+	newNode.count = 1
 
 	// 中間ノードに両方の子を接続
 	if len(childRemainingLabel) > 0 {
@@ -193,48 +320,64 @@ func (t *Trie) splitNodeWithNewBranch(parent *Node, child *Node, firstByte byte,
 	}
 
 	// 親ノードに中間ノードを接続
-	parent.children[firstByte] = intermediateNode
+	parent.AddChild(firstByte, intermediateNode)
 
-	return nil
+	return newNode, true, nil
 }
 
-// deleteNode 指定されたノードから始まってキーを削除
-func (t *Trie) deleteNode(node *Node, key string) error {
-	if len(key) == 0 {
-		// キーが完全に一致した場合、終端フラグを無効化
-		node.isEndOfKey = false
+// deleteNode キーを削除し、削除前の値と削除したかを返す
+// 削除した場合、経路上の各ノードの部分木キー数を1つ減らす
+func (t *Trie[V]) deleteNode(key string) (V, bool, error) {
+	var zero V
 
-		return nil
+	// 削除対象のノードを探す（見つかるまで経路は変更しない）
+	target := t.searchNode(key)
+	if target == nil || !target.isEndOfKey {
+		return zero, false, nil // キーが存在しない
 	}
 
-	firstByte := key[0]
+	// 経路上のノードのキー数を減らしながら降下し、削除対象の親と祖父母を記録
+	// 整理が必要になるのは削除対象とその親だけなので、経路全体は保持しない
+	var parent, grandparent *Node[V]
 
-	// 対応する子ノードが存在しない場合、削除対象なし
-	if !node.HasChild(firstByte) {
-		return nil // キーが存在しないが、エラーではない
-	}
+	node := t.root
+
+	for {
+		node.count--
 
-	child, _ := node.GetChild(firstByte)
+		if len(key) == 0 {
+			break
+		}
 
-	// ラベルがキーのプレフィックスとして一致しない場合
-	if len(key) < len(child.label) || key[:len(child.label)] != child.label {
-		return nil // キーが存在しない
+		child, _ := node.GetChild(key[0])
+		key = key[len(child.label):]
+		grandparent, parent, node = parent, node, child
 	}
 
-	// ラベルが完全に一致する場合、残りのキーで再帰的に削除
-	remaining := key[len(child.label):]
+	// キーが完全に一致した場合、終端フラグを無効化して値を解放
+	old := node.value
+	node.isEndOfKey = false
+	node.value = zero
 
-	err := t.deleteNode(child, remaining)
-	if err != nil {
-		return err
+	// 削除後、不要になったノードの削除と再圧縮（根ノードは対象外）
+	if parent == nil {
+		return old, true, nil
 	}
 
-	// 削除後、子ノードが不要になった場合の整理
-	return t.cleanupAfterDelete(node, child, firstByte)
+	err := t.cleanupAfterDelete(parent, node, node.label[0])
+	if err != nil || grandparent == nil {
+		return old, true, err
+	}
+
+	return old, true, t.cleanupAfterDelete(grandparent, parent, parent.label[0])
 }
 
 // cleanupAfterDelete 削除後のノード整理
-func (t *Trie) cleanupAfterDelete(parent *Node, child *Node, firstByte byte) error {
+// 子ノードが不要になれば削除し、非終端で子が1つだけになれば孫ノードと結合する
+// 子の削除で親ノード自身の子が1つになった場合は、呼び出し元が1つ上の階層で同じ整理を行う
+// 正規形の非終端ノードは2つ以上の子を持つため、1つのキーの削除で整理が必要なのは削除対象とその親だけ
+// （部分木を切り離すDeletePrefixでは経路を根まで戻りながら整理する。根ノードは対象外）
+func (t *Trie[V]) cleanupAfterDelete(parent *Node[V], child *Node[V], firstByte byte) error {
 	// 子ノードが終端でなく、子も持たない場合は削除
 	if !child.isEndOfKey && child.ChildrenCount() == 0 {
 		parent.RemoveChild(firstByte)
@@ -245,38 +388,55 @@ func (t *Trie) cleanupAfterDelete(parent *Node, child *Node, firstByte byte) err
 	// 子ノードが終端でなく、子を1つだけ持つ場合は圧縮
 	if !child.isEndOfKey && child.ChildrenCount() == 1 {
 		// 唯一の孫ノードを取得
-		var grandchild *Node
-		for _, v := range child.children {
-			grandchild = v
-
-			break
-		}
+		grandchild := child.childAt(0)
 
 		// 子ノードのラベルと孫ノードのラベルを結合
+		// 子ノードは終端でないため、孫ノードの部分木キー数は変わらない
 		combinedLabel := child.label + grandchild.label
 		grandchild.label = combinedLabel
 
 		// 親ノードに孫ノードを直接接続
-		parent.children[firstByte] = grandchild
+		parent.AddChild(firstByte, grandchild)
 	}
 
 	return nil
 }
 
-// findKeysWithPrefix プレフィックスマッチングでキーを検索
-func (t *Trie) findKeysWithPrefix(node *Node, currentKey, prefix string, result *[]string) {
-	// 現在のキーが指定されたプレフィックスで始まる場合
-	if node.isEndOfKey && len(currentKey) >= len(prefix) &&
-		(prefix == "" || currentKey[:len(prefix)] == prefix) {
-		*result = append(*result, currentKey)
-	}
+// findPrefixNode プレフィックスを持つキーがすべて含まれる部分木の根ノードと、そのノードまでのキーを取得
+// プレフィックスがエッジラベルの途中で終わる場合は、そのラベルを持つノードを返す
+// depthには根から降下したエッジの数を返す（見つからなかった場合も到達した深さを返す）
+func (t *Trie[V]) findPrefixNode(prefix string) (*Node[V], string, int) {
+	node := t.root
+	consumed := 0
+	depth := 0
+
+	for consumed < len(prefix) {
+		remaining := prefix[consumed:]
+
+		child, exists := node.GetChild(remaining[0])
+		if !exists {
+			return nil, "", depth
+		}
 
-	// 子ノードを探索
-	for _, child := range node.children {
-		newKey := currentKey + child.label
-		// プレフィックスの可能性がある場合のみ再帰
-		if len(newKey) >= len(prefix) || len(prefix) >= len(newKey) {
-			t.findKeysWithPrefix(child, newKey, prefix, result)
+		depth++
+
+		// プレフィックスの残りがラベル内で終わる場合
+		if len(remaining) <= len(child.label) {
+			if child.label[:len(remaining)] != remaining {
+				return nil, "", depth
+			}
+
+			return child, prefix[:consumed] + child.label, depth
+		}
+
+		// ラベル全体が一致しない場合、該当するキーは存在しない
+		if remaining[:len(child.label)] != child.label {
+			return nil, "", depth
 		}
+
+		consumed += len(child.label)
+		node = child
 	}
+
+	return node, prefix, depth
 }
@@ -1,6 +1,7 @@
 package patriciatrie
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,7 +16,7 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, trie)
 	assert.NotNil(t, trie.root)
 	assert.False(t, trie.root.isEndOfKey)
-	assert.Empty(t, trie.root.children)
+	assert.True(t, trie.root.IsLeaf())
 }
 
 func TestTrie_Insert(t *testing.T) {
@@ -46,7 +47,7 @@ func TestTrie_Insert(t *testing.T) {
 			trie := New()
 
 			for _, key := range tt.keys {
-				err := trie.Insert(key)
+				_, err := trie.Insert(key)
 				require.NoError(t, err)
 			}
 		})
@@ -61,7 +62,7 @@ func TestTrie_Search(t *testing.T) {
 
 	// キーを挿入
 	for _, key := range keys {
-		err := trie.Insert(key)
+		_, err := trie.Insert(key)
 		require.NoError(t, err)
 	}
 
@@ -97,12 +98,12 @@ func TestTrie_Delete(t *testing.T) {
 
 	// キーを挿入
 	for _, key := range keys {
-		err := trie.Insert(key)
+		_, err := trie.Insert(key)
 		require.NoError(t, err)
 	}
 
 	// 削除テスト
-	err := trie.Delete("cat")
+	_, err := trie.Delete("cat")
 	require.NoError(t, err)
 
 	// 削除されたキーは見つからない
@@ -113,6 +114,131 @@ func TestTrie_Delete(t *testing.T) {
 	assert.True(t, trie.Search("dog"))
 }
 
+func TestTrie_PutGet(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+
+	_, err := trie.Put("cat", 1)
+	require.NoError(t, err)
+	_, err = trie.Put("cats", 2)
+	require.NoError(t, err)
+	_, err = trie.Put("", "root")
+	require.NoError(t, err)
+	_, err = trie.Insert("dog")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected interface{}
+		found    bool
+	}{
+		{"値あり: cat", "cat", 1, true},
+		{"値あり: cats", "cats", 2, true},
+		{"空文字列", "", "root", true},
+		{"値なしで挿入されたキー", "dog", nil, true},
+		{"存在しないキー: ca", "ca", nil, false},
+		{"存在しないキー: dogs", "dogs", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, found := trie.Get(tt.key)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestTrie_PutOverwrite(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+
+	_, err := trie.Put("cat", 1)
+	require.NoError(t, err)
+	_, err = trie.Put("cat", 2)
+	require.NoError(t, err)
+
+	value, found := trie.Get("cat")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	// Insertは既存の値を上書きしない
+	_, err = trie.Insert("cat")
+	require.NoError(t, err)
+
+	value, found = trie.Get("cat")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+}
+
+func TestTrie_DeleteReturnsValue(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+
+	_, err := trie.Put("cat", 1)
+	require.NoError(t, err)
+	_, err = trie.Put("cats", 2)
+	require.NoError(t, err)
+
+	old, err := trie.Delete("cat")
+	require.NoError(t, err)
+	assert.Equal(t, 1, old)
+
+	_, found := trie.Get("cat")
+	assert.False(t, found)
+
+	value, found := trie.Get("cats")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	// 存在しないキーの削除はErrKeyNotFoundを返す
+	old, err = trie.Delete("dog")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Nil(t, old)
+
+	// 再挿入したキーには以前の値が残らない
+	_, err = trie.Insert("cat")
+	require.NoError(t, err)
+
+	value, found = trie.Get("cat")
+	assert.True(t, found)
+	assert.Nil(t, value)
+}
+
+func TestNewTrie_Typed(t *testing.T) {
+	t.Parallel()
+
+	trie := NewTrie[int]()
+
+	_, err := trie.Put("cat", 1)
+	require.NoError(t, err)
+	_, err = trie.Put("cats", 2)
+	require.NoError(t, err)
+	_, err = trie.Insert("dog")
+	require.NoError(t, err)
+
+	value, found := trie.Get("cats")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	// 値なしで挿入されたキーはゼロ値を持つ
+	value, found = trie.Get("dog")
+	assert.True(t, found)
+	assert.Equal(t, 0, value)
+
+	old, err := trie.Delete("cat")
+	require.NoError(t, err)
+	assert.Equal(t, 1, old)
+
+	assert.ElementsMatch(t, []string{"cats", "dog"}, trie.FindByPrefix(""))
+}
+
 func TestTrie_FindByPrefix(t *testing.T) {
 	t.Parallel()
 
@@ -121,7 +247,7 @@ func TestTrie_FindByPrefix(t *testing.T) {
 
 	// キーを挿入
 	for _, key := range keys {
-		err := trie.Insert(key)
+		_, err := trie.Insert(key)
 		require.NoError(t, err)
 	}
 
@@ -150,6 +276,26 @@ func TestTrie_FindByPrefix(t *testing.T) {
 			prefix:   "xyz",
 			expected: []string{},
 		},
+		{
+			name:     "空のプレフィックス",
+			prefix:   "",
+			expected: []string{"cat", "cats", "dog", "dogs", "elephant"},
+		},
+		{
+			name:     "ラベル途中で不一致",
+			prefix:   "elk",
+			expected: []string{},
+		},
+		{
+			name:     "キーより長いプレフィックス",
+			prefix:   "catsup",
+			expected: []string{},
+		},
+		{
+			name:     "キーと完全一致するプレフィックス",
+			prefix:   "cats",
+			expected: []string{"cats"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -161,3 +307,318 @@ func TestTrie_FindByPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestTrie_FindByPrefixOrder(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	keys := []string{"dogs", "cat", "b", "cats", "dog", "ca", "a", "catalog"}
+
+	for _, key := range keys {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	// 昇順は挿入順に関わらずバイト順で安定
+	assert.Equal(t, []string{"a", "b", "ca", "cat", "catalog", "cats", "dog", "dogs"}, trie.FindByPrefix(""))
+	assert.Equal(t, []string{"ca", "cat", "catalog", "cats"}, trie.FindByPrefixOrder("ca", Ascending))
+
+	// 降順
+	assert.Equal(t, []string{"cats", "catalog", "cat", "ca"}, trie.FindByPrefixOrder("ca", Descending))
+}
+
+func TestTrie_Len(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	assert.Equal(t, 0, trie.Len())
+
+	for _, key := range []string{"cat", "cats", "ca", "dog", "", "cat"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	// 重複したキーは数えない
+	assert.Equal(t, 5, trie.Len())
+
+	_, err := trie.Delete("cat")
+	require.NoError(t, err)
+
+	// 存在しないキーの削除では変化しない
+	_, err = trie.Delete("cow")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = trie.Delete("c")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	assert.Equal(t, 4, trie.Len())
+}
+
+func TestTrie_CountPrefix(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"cat", "cats", "catalog", "ca", "dog", "dogs", "elephant"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		expected int
+	}{
+		{"空のプレフィックス", "", 7},
+		{"プレフィックス: ca", "ca", 4},
+		{"プレフィックス: cat", "cat", 3},
+		{"ラベル途中で終わるプレフィックス", "cata", 1},
+		{"プレフィックス: d", "d", 2},
+		{"マッチしないプレフィックス", "xyz", 0},
+		{"キーより長いプレフィックス", "elephants", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, trie.CountPrefix(tt.prefix))
+			assert.Len(t, trie.FindByPrefix(tt.prefix), tt.expected)
+		})
+	}
+}
+
+func TestTrie_CountPrefixAfterMixedOperations(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	keys := generateRandomKeys(500)
+	present := make(map[string]bool)
+
+	for i, key := range keys {
+		if i%3 == 2 {
+			_, err := trie.Delete(keys[i/2])
+			if present[keys[i/2]] {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrKeyNotFound)
+			}
+
+			delete(present, keys[i/2])
+
+			continue
+		}
+
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+		present[key] = true
+	}
+
+	assert.Equal(t, len(present), trie.Len())
+
+	for _, prefix := range []string{"", "a", "b", "ab", "xyz"} {
+		assert.Len(t, trie.FindByPrefix(prefix), trie.CountPrefix(prefix), "prefix %q", prefix)
+	}
+}
+
+func TestTrie_DeletePrefix(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{
+		"tenant/1/a", "tenant/1/b", "tenant/12/a", "tenant/123/a", "tenant/123/b/c", "tenant/2", "user",
+	}
+
+	tests := []struct {
+		name      string
+		prefix    string
+		removed   int
+		remaining []string
+	}{
+		{
+			name:      "部分木の削除",
+			prefix:    "tenant/123/",
+			removed:   2,
+			remaining: []string{"tenant/1/a", "tenant/1/b", "tenant/12/a", "tenant/2", "user"},
+		},
+		{
+			name:      "ラベル途中で終わるプレフィックス",
+			prefix:    "tenant/12",
+			removed:   3,
+			remaining: []string{"tenant/1/a", "tenant/1/b", "tenant/2", "user"},
+		},
+		{
+			name:      "キーと一致するプレフィックス",
+			prefix:    "user",
+			removed:   1,
+			remaining: []string{"tenant/1/a", "tenant/1/b", "tenant/12/a", "tenant/123/a", "tenant/123/b/c", "tenant/2"},
+		},
+		{
+			name:      "マッチしないプレフィックス",
+			prefix:    "tenant/3",
+			removed:   0,
+			remaining: keys,
+		},
+		{
+			name:      "空のプレフィックス",
+			prefix:    "",
+			removed:   len(keys),
+			remaining: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trie := New()
+			for _, key := range keys {
+				_, err := trie.Insert(key)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.removed, trie.DeletePrefix(tt.prefix))
+			assert.Equal(t, tt.remaining, trie.FindByPrefix(""))
+			assert.Equal(t, len(tt.remaining), trie.Len())
+			assert.Equal(t, 0, trie.CountPrefix(tt.prefix))
+		})
+	}
+}
+
+func TestTrie_DeletePrefixRecompresses(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"abc", "abd", "abde"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	// "abd"以下を削除すると、"ab"の中間ノードは"abc"と結合される
+	assert.Equal(t, 2, trie.DeletePrefix("abd"))
+
+	child, exists := trie.root.GetChild('a')
+	require.True(t, exists)
+	assert.Equal(t, "abc", child.label)
+	assert.True(t, child.IsLeaf())
+}
+
+func TestTrie_InsertReportsNewKey(t *testing.T) {
+	t.Parallel()
+
+	trie := NewTrie[int]()
+
+	added, err := trie.Insert("cat")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = trie.Insert("cat")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// 既存ノードの分割で作られるキーも新規キー
+	added, err = trie.Put("ca", 1)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = trie.Put("ca", 2)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	added, err = trie.Insert("")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	assert.Equal(t, 3, trie.Len())
+}
+
+func TestTrie_DeleteNotFound(t *testing.T) {
+	t.Parallel()
+
+	newTrie := func(t *testing.T) *Trie[any] {
+		t.Helper()
+
+		trie := New()
+		for _, key := range []string{"cat", "cats"} {
+			_, err := trie.Insert(key)
+			require.NoError(t, err)
+		}
+
+		return trie
+	}
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"分岐の途中", "ca"},
+		{"子ノードなし", "dog"},
+		{"ラベル途中で不一致", "cab"},
+		{"既存キーより長い", "catsup"},
+		{"空文字列", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trie := newTrie(t)
+
+			_, err := trie.Delete(tt.key)
+			require.ErrorIs(t, err, ErrKeyNotFound)
+			assert.Equal(t, 2, trie.Len())
+		})
+	}
+
+	trie := newTrie(t)
+
+	_, err := trie.Delete("cat")
+	require.NoError(t, err)
+
+	// 2回目の削除は見つからない
+	_, err = trie.Delete("cat")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Equal(t, 1, trie.Len())
+}
+
+//nolint:paralleltest // AllocsPerRun must not run in parallel
+func TestTrie_SearchDoesNotAllocate(t *testing.T) {
+	trie := newIntTrie(t, "cat", "cats", "car", "dog", "do")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = trie.Search("cats")
+		_ = trie.Search("ca")
+		_, _ = trie.Get("do")
+		_, _, _ = trie.LongestPrefixOf("category")
+	})
+
+	assert.Zero(t, allocs)
+}
+
+func TestTrie_DeepKeys(t *testing.T) {
+	t.Parallel()
+
+	// "a", "aa", "aaa", ...と1バイトずつ長いキーで、キーの長さと同じ深さの経路を作る
+	const depth = 5000
+
+	trie := New()
+	for i := 1; i <= depth; i++ {
+		_, err := trie.Insert(strings.Repeat("a", i))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, trie.Validate())
+	assert.Equal(t, depth, trie.Len())
+	assert.True(t, trie.Search(strings.Repeat("a", depth)))
+	assert.Len(t, trie.FindByPrefix(strings.Repeat("a", depth-9)), 10)
+	assert.Len(t, trie.FindByPrefixOrder("", Descending), depth)
+
+	// 深い位置のキーを削除しても正規形を保つ
+	for i := depth; i >= 1; i -= 2 {
+		_, err := trie.Delete(strings.Repeat("a", i))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, trie.Validate())
+	assert.Equal(t, depth/2, trie.Len())
+	assert.False(t, trie.Search(strings.Repeat("a", depth)))
+	assert.True(t, trie.Search(strings.Repeat("a", depth-1)))
+}
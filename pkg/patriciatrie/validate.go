@@ -0,0 +1,71 @@
+package patriciatrie
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidStructure トライの構造がパトリシアトライの不変条件を満たさない
+var ErrInvalidStructure = errors.New("patriciatrie: invalid structure")
+
+// Validate トライが正規形（パス圧縮された状態）であることを検証
+//   - 根以外のノードは終端であるか、2つ以上の子を持つ
+//   - 根以外のノードのラベルは空でない
+//   - 子ノードのラベルの先頭バイトは親ノードでのキーと一致する
+//   - 部分木のキー数が実際のキー数と一致する
+func (t *Trie[V]) Validate() error {
+	if t.root.label != "" {
+		return fmt.Errorf("%w: root has label %q", ErrInvalidStructure, t.root.label)
+	}
+
+	_, err := t.validateNode(t.root, "", true)
+
+	return err
+}
+
+// validateNode 指定されたノード以下を検証し、部分木のキー数を返す
+func (t *Trie[V]) validateNode(node *Node[V], key string, isRoot bool) (int, error) {
+	if !isRoot {
+		if node.label == "" {
+			return 0, fmt.Errorf("%w: node under %q has empty label", ErrInvalidStructure, key)
+		}
+
+		if !node.isEndOfKey && node.ChildrenCount() < 2 {
+			return 0, fmt.Errorf("%w: non-terminal node %q has %d children", ErrInvalidStructure, key, node.ChildrenCount())
+		}
+	}
+
+	count := 0
+	if node.isEndOfKey {
+		count++
+	}
+
+	if !slices.IsSorted(node.childKeys) {
+		return 0, fmt.Errorf("%w: children of %q are not sorted", ErrInvalidStructure, key)
+	}
+
+	for i, b := range node.childKeys {
+		child := node.childAt(i)
+		if child == nil {
+			return 0, fmt.Errorf("%w: child %q of %q is missing", ErrInvalidStructure, b, key)
+		}
+
+		if child.label != "" && child.label[0] != b {
+			return 0, fmt.Errorf("%w: child %q of %q is stored under byte %q", ErrInvalidStructure, child.label, key, b)
+		}
+
+		childCount, err := t.validateNode(child, key+child.label, false)
+		if err != nil {
+			return 0, err
+		}
+
+		count += childCount
+	}
+
+	if count != node.count {
+		return 0, fmt.Errorf("%w: node %q counts %d keys but has %d", ErrInvalidStructure, key, node.count, count)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,194 @@
+package patriciatrie
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_Validate(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	require.NoError(t, trie.Validate())
+
+	for _, key := range []string{"cat", "cats", "ca", "dog", ""} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, trie.Validate())
+}
+
+func TestTrie_ValidateDetectsBrokenInvariants(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		corrupt func(trie *Trie[any])
+	}{
+		{
+			name: "子を1つだけ持つ非終端ノード",
+			corrupt: func(trie *Trie[any]) {
+				child, _ := trie.root.GetChild('c')
+				child.isEndOfKey = false
+				child.count--
+				trie.root.count--
+			},
+		},
+		{
+			name: "空のラベル",
+			corrupt: func(trie *Trie[any]) {
+				child, _ := trie.root.GetChild('d')
+				child.label = ""
+			},
+		},
+		{
+			name: "ラベルの先頭バイトとキーの不一致",
+			corrupt: func(trie *Trie[any]) {
+				child, _ := trie.root.GetChild('d')
+				child.label = "fog"
+			},
+		},
+		{
+			name: "部分木のキー数の不一致",
+			corrupt: func(trie *Trie[any]) {
+				trie.root.count++
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trie := New()
+			for _, key := range []string{"ca", "cat", "dog"} {
+				_, err := trie.Insert(key)
+				require.NoError(t, err)
+			}
+
+			tt.corrupt(trie)
+			require.ErrorIs(t, trie.Validate(), ErrInvalidStructure)
+		})
+	}
+}
+
+func TestTrie_DeleteKeepsCanonicalForm(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		keys    []string
+		deletes []string
+	}{
+		{"中間キーの削除", []string{"a", "ab", "abc"}, []string{"ab"}},
+		{"分岐が1つになる", []string{"abc", "abd", "ab"}, []string{"ab", "abd"}},
+		{"根直下の連鎖", []string{"x", "xy", "xyz", "xyw"}, []string{"x", "xyw", "xy"}},
+		{"空文字列のキー", []string{"", "a", "b"}, []string{"", "a"}},
+		{"すべて削除", []string{"a", "ab", "b"}, []string{"ab", "a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			trie := New()
+			for _, key := range tt.keys {
+				_, err := trie.Insert(key)
+				require.NoError(t, err)
+			}
+
+			for _, key := range tt.deletes {
+				_, err := trie.Delete(key)
+				require.NoError(t, err)
+				require.NoError(t, trie.Validate(), "after deleting %q", key)
+			}
+		})
+	}
+}
+
+func TestTrie_MixedOperationsKeepCanonicalForm(t *testing.T) {
+	t.Parallel()
+
+	// 狭い文字種で共通プレフィックスを多く発生させる
+	const charset = "abc"
+
+	rng := rand.New(rand.NewSource(1))
+	trie := New()
+	present := make(map[string]bool)
+
+	for range 5000 {
+		key := make([]byte, rng.Intn(6))
+		for i := range key {
+			key[i] = charset[rng.Intn(len(charset))]
+		}
+
+		switch rng.Intn(8) {
+		case 0, 1, 2, 3:
+			added, err := trie.Insert(string(key))
+			require.NoError(t, err)
+			assert.Equal(t, !present[string(key)], added)
+
+			present[string(key)] = true
+		case 7:
+			removed := 0
+
+			for k := range present {
+				if strings.HasPrefix(k, string(key)) {
+					delete(present, k)
+
+					removed++
+				}
+			}
+
+			assert.Equal(t, removed, trie.DeletePrefix(string(key)))
+		default:
+			_, err := trie.Delete(string(key))
+			if present[string(key)] {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrKeyNotFound)
+			}
+
+			delete(present, string(key))
+		}
+
+		require.NoError(t, trie.Validate())
+	}
+
+	assert.Equal(t, len(present), trie.Len())
+}
+
+func TestTrie_DenseNodesKeepCanonicalForm(t *testing.T) {
+	t.Parallel()
+
+	// 根ノードと"x"の下に256通りの分岐を作り、node256への拡大とnode4までの縮小を起こす
+	trie := NewTrie[int]()
+
+	for i := range childTableSize {
+		_, err := trie.Put(string([]byte{byte(i)}), i)
+		require.NoError(t, err)
+
+		_, err = trie.Put("x"+string([]byte{byte(i)}), i)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, trie.Validate())
+	assert.Equal(t, 2*childTableSize, trie.Len())
+
+	for i := range childTableSize - 1 {
+		_, err := trie.Delete("x" + string([]byte{byte(i)}))
+		require.NoError(t, err)
+		require.NoError(t, trie.Validate())
+	}
+
+	value, found := trie.Get("x\xff")
+	assert.True(t, found)
+	assert.Equal(t, 255, value)
+	assert.Equal(t, 1, trie.CountPrefix("x\xff"))
+	assert.Equal(t, childTableSize+1, trie.Len())
+}
@@ -0,0 +1,80 @@
+package patriciatrie
+
+// Order キーの列挙順序
+type Order int
+
+const (
+	// Ascending バイト順の昇順
+	Ascending Order = iota
+	// Descending バイト順の降順
+	Descending
+)
+
+// WalkFunc Walkで各キーに対して呼ばれる関数（falseを返すと走査を中断）
+type WalkFunc[V any] func(key string, value V) bool
+
+// Walk トライ全体のキーと値を指定された順序で走査
+func (t *Trie[V]) Walk(order Order, fn WalkFunc[V]) {
+	t.walkNode(t.root, t.root.label, order, fn)
+}
+
+// walkFrame 走査中のノードと、次に辿る子ノードの位置、ノードまでのキーの長さ
+type walkFrame[V any] struct {
+	node   *Node[V]
+	next   int
+	keyLen int
+}
+
+// walkNode 指定されたノード以下を走査（中断された場合はfalseを返す）
+func (t *Trie[V]) walkNode(node *Node[V], currentKey string, order Order, fn WalkFunc[V]) bool {
+	return t.traverse(node, currentKey, order, func(node *Node[V], key []byte, _ int) bool {
+		// キーの文字列は終端ノードでのみ作成する
+		return !node.isEndOfKey || fn(string(key), node.value)
+	})
+}
+
+// traverse 指定されたノード以下のすべてのノードを再帰を使わずに巡回し、visitに渡す（中断された場合はfalseを返す）
+// 昇順では親ノードを子孫より先、降順では後に訪問する
+// keyは巡回全体で使い回すバッファのため、visitの呼び出し中のみ有効
+// depthには開始ノードから降下したエッジの数を渡す
+func (t *Trie[V]) traverse(node *Node[V], prefix string, order Order, visit func(node *Node[V], key []byte, depth int) bool) bool {
+	key := append(make([]byte, 0, len(prefix)+64), prefix...)
+	stack := append(make([]walkFrame[V], 0, 32), walkFrame[V]{node: node, keyLen: len(key)})
+
+	if order == Ascending && !visit(node, key, 0) {
+		return false
+	}
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+
+		if top.next < top.node.ChildrenCount() {
+			// 降順では子ノードを逆から辿る
+			i := top.next
+			if order == Descending {
+				i = top.node.ChildrenCount() - 1 - i
+			}
+
+			top.next++
+
+			child := top.node.childAt(i)
+			key = append(key[:top.keyLen], child.label...)
+			stack = append(stack, walkFrame[V]{node: child, keyLen: len(key)})
+
+			if order == Ascending && !visit(child, key, len(stack)-1) {
+				return false
+			}
+
+			continue
+		}
+
+		// すべての子孫を辿り終えたノードを取り除く
+		stack = stack[:len(stack)-1]
+
+		if order == Descending && !visit(top.node, key[:top.keyLen], len(stack)) {
+			return false
+		}
+	}
+
+	return true
+}
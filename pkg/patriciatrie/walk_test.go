@@ -0,0 +1,62 @@
+package patriciatrie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_Walk(t *testing.T) {
+	t.Parallel()
+
+	trie := newIntTrie(t, "b", "abc", "", "ab", "a", "ba")
+
+	tests := []struct {
+		name     string
+		order    Order
+		expected []string
+	}{
+		{"昇順", Ascending, []string{"", "a", "ab", "abc", "b", "ba"}},
+		{"降順", Descending, []string{"ba", "b", "abc", "ab", "a", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+
+			trie.Walk(tt.order, func(key string, value int) bool {
+				expected, _ := trie.Get(key)
+				assert.Equal(t, expected, value)
+
+				got = append(got, key)
+
+				return true
+			})
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestTrie_WalkStop(t *testing.T) {
+	t.Parallel()
+
+	trie := New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		_, err := trie.Insert(key)
+		require.NoError(t, err)
+	}
+
+	var got []string
+
+	trie.Walk(Ascending, func(key string, _ any) bool {
+		got = append(got, key)
+
+		return len(got) < 2
+	})
+
+	assert.Equal(t, []string{"a", "b"}, got)
+}
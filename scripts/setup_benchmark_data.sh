@@ -37,7 +37,7 @@ setup_japanese_data() {
             echo "    📥 small_lex.zip をダウンロード中..."
             curl -L -o "${japanese_dir}/small_lex.zip" "${base_url}/small_lex.zip"
             unzip -o -q "${japanese_dir}/small_lex.zip" -d "${japanese_dir}"
-            cut -d',' -f1 "${japanese_dir}/small_lex.csv" | grep -v '^$' | sort -u > "${small_words}"
+            cut -d',' -f1 "${japanese_dir}/small_lex.csv" | grep -v '^$' | LC_ALL=C sort -u > "${small_words}"
             echo "    ✅ small_lex: $(wc -l < "${small_words}")語"
         fi
         
@@ -46,7 +46,7 @@ setup_japanese_data() {
             echo "    📥 core_lex.zip をダウンロード中..."
             curl -L -o "${japanese_dir}/core_lex.zip" "${base_url}/core_lex.zip"
             unzip -o -q "${japanese_dir}/core_lex.zip" -d "${japanese_dir}"
-            cut -d',' -f1 "${japanese_dir}/core_lex.csv" | grep -v '^$' | sort -u > "${core_words}"
+            cut -d',' -f1 "${japanese_dir}/core_lex.csv" | grep -v '^$' | LC_ALL=C sort -u > "${core_words}"
             echo "    ✅ core_lex: $(wc -l < "${core_words}")語"
         fi
         
@@ -55,13 +55,13 @@ setup_japanese_data() {
             echo "    📥 notcore_lex.zip をダウンロード中..."
             curl -L -o "${japanese_dir}/notcore_lex.zip" "${base_url}/notcore_lex.zip"
             unzip -o -q "${japanese_dir}/notcore_lex.zip" -d "${japanese_dir}"
-            cut -d',' -f1 "${japanese_dir}/notcore_lex.csv" | grep -v '^$' | sort -u > "${notcore_words}"
+            cut -d',' -f1 "${japanese_dir}/notcore_lex.csv" | grep -v '^$' | LC_ALL=C sort -u > "${notcore_words}"
             echo "    ✅ notcore_lex: $(wc -l < "${notcore_words}")語"
         fi
         
-        # 全辞書統合（重複削除）
+        # 全辞書統合（重複削除、トライの一括構築に合わせてバイト順でソート）
         echo "  🔄 全辞書データを統合中..."
-        sort -u "${small_words}" "${core_words}" "${notcore_words}" > "${full_words}"
+        LC_ALL=C sort -u "${small_words}" "${core_words}" "${notcore_words}" > "${full_words}"
         echo "  ✅ 統合辞書: $(wc -l < "${full_words}")語"
         
         # テスト用ファイル作成（1000語）- 漢字で始まる単語のみ